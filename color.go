@@ -0,0 +1,169 @@
+package dump
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape codes used by ColorWriter
+const (
+	colorReset  = "\x1b[0m"
+	colorKey    = "\x1b[36m"
+	colorString = "\x1b[32m"
+	colorNumber = "\x1b[33m"
+	colorBool   = "\x1b[35m"
+	colorNil    = "\x1b[31m"
+	colorMeta   = "\x1b[90m"
+)
+
+// ColorWriter highlights the lines written by Fdump and Tdump with ANSI escape codes: keys, values according
+// to their type (strings, numbers, bools and nil values) and metadata keys are rendered with different colors.
+// Incomplete lines are buffered until their end is written or Flush is called.
+type ColorWriter struct {
+	// Encoder provides the names of the metadata keys and the way keys are built, the keys of
+	// a default encoder are recognized if it is nil
+	Encoder *Encoder
+	w       io.Writer
+	buf     []byte
+}
+
+// NewColorWriter returns a ColorWriter over w if w is a terminal, or w itself if it is not
+// or if the NO_COLOR environment variable is set.
+func NewColorWriter(w io.Writer) io.Writer {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" || !isTerminal(w) {
+		return w
+	}
+	return Colorize(w)
+}
+
+// Colorize returns a ColorWriter over w, whatever w is
+func Colorize(w io.Writer) *ColorWriter {
+	return &ColorWriter{w: w}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Write colorizes the complete lines of p and writes them to the underlying writer
+func (c *ColorWriter) Write(p []byte) (int, error) {
+	c.buf = append(c.buf, p...)
+	for {
+		i := bytes.IndexByte(c.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(c.buf[:i])
+		c.buf = c.buf[i+1:]
+		if _, err := io.WriteString(c.w, c.colorizeLine(line)+"\n"); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush colorizes and writes the buffered incomplete line, if any
+func (c *ColorWriter) Flush() error {
+	if len(c.buf) == 0 {
+		return nil
+	}
+	line := string(c.buf)
+	c.buf = nil
+	_, err := io.WriteString(c.w, c.colorizeLine(line))
+	return err
+}
+
+// colorizeLine colorizes a "key: value" line written by Fdump, or a "label: (type) len=n value" line written by Tdump
+func (c *ColorWriter) colorizeLine(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(trimmed)]
+
+	var key, rest string
+	if i := strings.Index(trimmed, ": "); i >= 0 {
+		key, rest = trimmed[:i], trimmed[i+2:]
+	} else if strings.HasSuffix(trimmed, ":") {
+		key = trimmed[:len(trimmed)-1]
+	} else {
+		return line
+	}
+
+	if c.isMetaKey(key) {
+		return indent + colorMeta + trimmed + colorReset
+	}
+
+	res := indent + colorKey + key + colorReset + ":"
+	if rest == "" {
+		return res
+	}
+
+	var details []string
+	if strings.HasPrefix(rest, "(") {
+		if i := strings.Index(rest, ") "); i >= 0 {
+			details, rest = append(details, rest[:i+1]), rest[i+2:]
+		} else if strings.HasSuffix(rest, ")") {
+			details, rest = append(details, rest), ""
+		}
+		if strings.HasPrefix(rest, "len=") {
+			i := strings.IndexByte(rest, ' ')
+			if i < 0 {
+				i = len(rest)
+			}
+			details = append(details, rest[:i])
+			rest = strings.TrimPrefix(rest[i:], " ")
+		}
+	}
+	if len(details) > 0 {
+		res += " " + colorMeta + strings.Join(details, " ") + colorReset
+	}
+	if rest != "" {
+		res += " " + valueColor(rest) + rest + colorReset
+	}
+	return res
+}
+
+// isMetaKey returns true if the last segment of the key is the name of a metadata key, such as __Len__
+func (c *ColorWriter) isMetaKey(key string) bool {
+	e := c.Encoder
+	if e == nil {
+		e = NewDefaultEncoder()
+	}
+	key = strings.TrimRight(key, " ")
+	if e.Prefix != "" {
+		key = strings.TrimPrefix(key, e.Prefix+e.Separator)
+	}
+	segments := []string{key}
+	if e.Separator != "" {
+		segments = strings.Split(key, e.Separator)
+	}
+	last, level := segments[len(segments)-1], len(segments)-1
+	names := e.metaKeys()
+	for _, name := range []string{names.Len, names.Type, names.Kind, names.Addr, names.Nil} {
+		if last == format(name, e.Formatters, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueColor(v string) string {
+	switch v {
+	case "<nil>", "null":
+		return colorNil
+	case "true", "false":
+		return colorBool
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return colorNumber
+	}
+	return colorString
+}
@@ -0,0 +1,72 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestColorWriter(t *testing.T) {
+	type T struct {
+		A int
+		B string
+		C []bool
+		D *T
+	}
+
+	out := &bytes.Buffer{}
+	assert.Equal(t, out, dump.NewColorWriter(out))
+
+	e := dump.NewEncoder(dump.Colorize(out))
+	e.ExtraFields.Len = true
+	err := e.Fdump(T{A: 23, B: "foo bar", C: []bool{true}})
+	assert.NoError(t, err)
+
+	expected := "\x1b[36mT.A\x1b[0m: \x1b[33m23\x1b[0m\n" +
+		"\x1b[36mT.B\x1b[0m: \x1b[32mfoo bar\x1b[0m\n" +
+		"\x1b[36mT.C.C0\x1b[0m: \x1b[35mtrue\x1b[0m\n" +
+		"\x1b[90mT.C.__Len__: 1\x1b[0m\n" +
+		"\x1b[36mT.D\x1b[0m:\n"
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	w := dump.Colorize(out)
+	e = dump.NewEncoder(w)
+	e.Separator = "_"
+	e.Prefix = "APP"
+	e.DisableTypePrefix = true
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultUpperCaseFormatter()}
+	e.ExtraFields.Len = true
+	e.MetaKeys.Len = "Count"
+	w.Encoder = e
+	err = e.Fdump(struct{ B []string }{B: []string{"b1"}})
+	assert.NoError(t, err)
+
+	expected = "\x1b[36mAPP_B_B0\x1b[0m: \x1b[32mb1\x1b[0m\n" +
+		"\x1b[90mAPP_B_COUNT: 1\x1b[0m\n"
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	e = dump.NewEncoder(dump.Colorize(out))
+	err = e.Fdump(map[string]string{"A__": "a", "B": "__b__"})
+	assert.NoError(t, err)
+
+	expected = "\x1b[36mA__\x1b[0m: \x1b[32ma\x1b[0m\n" +
+		"\x1b[36mB\x1b[0m: \x1b[32m__b__\x1b[0m\n"
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	e = dump.NewEncoder(dump.Colorize(out))
+	err = e.Tdump(T{C: []bool{}})
+	assert.NoError(t, err)
+
+	expected = "\x1b[36mT\x1b[0m: \x1b[90m(go-dump_test.T)\x1b[0m\n" +
+		"  \x1b[36mA\x1b[0m: \x1b[90m(int)\x1b[0m \x1b[33m0\x1b[0m\n" +
+		"  \x1b[36mB\x1b[0m: \x1b[90m(string)\x1b[0m\n" +
+		"  \x1b[36mC\x1b[0m: \x1b[90m([]bool) len=0\x1b[0m\n" +
+		"  \x1b[36mD\x1b[0m: \x1b[90m(*go-dump_test.T)\x1b[0m \x1b[31m<nil>\x1b[0m\n"
+	assert.Equal(t, expected, out.String())
+}
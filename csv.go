@@ -0,0 +1,64 @@
+package dump
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// WriteCSV writes the items of the array or slice i to w as CSV. Each item is dumped on its own, so keys do not
+// hold the index of the item. The union of the keys of all the items is written as header, and each item
+// is written as a row, with empty cells for the keys it does not have.
+func (e *Encoder) WriteCSV(w io.Writer, i interface{}) error {
+	return e.writeCSV(w, i, ',')
+}
+
+// WriteTSV writes the items of the array or slice i to w as tab separated values, the same way as WriteCSV
+func (e *Encoder) WriteTSV(w io.Writer, i interface{}) error {
+	return e.writeCSV(w, i, '\t')
+}
+
+func (e *Encoder) writeCSV(w io.Writer, i interface{}, comma rune) error {
+	v := valueFromInterface(i)
+	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+		return fmt.Errorf("unable to write %T as CSV: not an array or a slice", i)
+	}
+
+	rows := make([]map[string]string, v.Len())
+	columns := map[string]struct{}{}
+	for n := range rows {
+		row, err := e.ToStringMap(v.Index(n).Interface())
+		if err != nil {
+			return err
+		}
+		for k := range row {
+			columns[k] = struct{}{}
+		}
+		rows[n] = row
+	}
+
+	header := make([]string, 0, len(columns))
+	for k := range columns {
+		header = append(header, k)
+	}
+	sort.Slice(header, func(i, j int) bool { return naturalLess(header[i], header[j]) })
+
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for n, k := range header {
+			record[n] = row[k]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
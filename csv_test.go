@@ -0,0 +1,46 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestWriteCSV(t *testing.T) {
+	type Record struct {
+		Name   string
+		Tags   []string
+		Labels map[string]string
+		Nested Tbis
+	}
+
+	records := []Record{
+		{Name: "first", Tags: []string{"a", "b"}, Nested: Tbis{"x", "y, z"}},
+		{Name: "second", Labels: map[string]string{"env": "prod"}},
+	}
+
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	err := e.WriteCSV(out, records)
+	assert.NoError(t, err)
+
+	expected := `Labels.env,Name,Nested.Cbis,Nested.Cter,Tags.Tags0,Tags.Tags1
+,first,x,"y, z",a,b
+prod,second,,,,
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteTSV(out, records[:1])
+	assert.NoError(t, err)
+
+	expected = "Name\tNested.Cbis\tNested.Cter\tTags.Tags0\tTags.Tags1\nfirst\tx\ty, z\ta\tb\n"
+	assert.Equal(t, expected, out.String())
+
+	err = e.WriteCSV(out, records[0])
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package dump
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DeepDecoderFunc decodes a string value into the value to dump instead, such as a map or a slice.
+// It returns false if the string is not in its format.
+type DeepDecoderFunc func(s string) (interface{}, bool)
+
+// DeepDecoder decodes the string values of the keys it matches to dump their content, as ExtraFields.DeepJSON
+// does for JSON
+type DeepDecoder struct {
+	Decode DeepDecoderFunc
+	// Keys restricts the decoder to the keys matching one of the patterns, with the syntax of path.Match.
+	// The decoder applies to every key if Keys is empty.
+	Keys []string
+}
+
+func (d DeepDecoder) match(k string) bool {
+	if len(d.Keys) == 0 {
+		return true
+	}
+	for _, p := range d.Keys {
+		if ok, _ := path.Match(p, k); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeXML decodes XML documents into maps holding the root element. Elements are maps of their attributes,
+// prefixed by @, and of their children, repeated children being slices, or their text if they have neither.
+// The text of the elements having attributes or children is held by the #text key.
+func DecodeXML() DeepDecoderFunc {
+	return func(s string) (interface{}, bool) {
+		if !strings.HasPrefix(strings.TrimSpace(s), "<") {
+			return nil, false
+		}
+		d := xml.NewDecoder(strings.NewReader(s))
+		for {
+			t, err := d.Token()
+			if err != nil {
+				return nil, false
+			}
+			if start, ok := t.(xml.StartElement); ok {
+				v, err := decodeXMLElement(d, start)
+				if err != nil {
+					return nil, false
+				}
+				return map[string]interface{}{start.Name.Local: v}, true
+			}
+		}
+	}
+}
+
+func decodeXMLElement(d *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	m := map[string]interface{}{}
+	for _, a := range start.Attr {
+		m["@"+a.Name.Local] = a.Value
+	}
+	var text strings.Builder
+	for {
+		t, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			v, err := decodeXMLElement(d, t)
+			if err != nil {
+				return nil, err
+			}
+			switch prev := m[t.Name.Local].(type) {
+			case nil:
+				m[t.Name.Local] = v
+			case []interface{}:
+				m[t.Name.Local] = append(prev, v)
+			default:
+				m[t.Name.Local] = []interface{}{prev, v}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				m["#text"] = s
+			}
+			return m, nil
+		}
+	}
+}
+
+// DecodeBase64JSON decodes the base64 encoded JSON arrays and objects, with the standard or the URL alphabet,
+// padded or not
+func DecodeBase64JSON() DeepDecoderFunc {
+	return func(s string) (interface{}, bool) {
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+			b, err := enc.DecodeString(strings.TrimSpace(s))
+			if err != nil {
+				continue
+			}
+			var v interface{}
+			if err := decodeJSON(string(b), &v); err != nil {
+				return nil, false
+			}
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				return v, true
+			}
+			return nil, false
+		}
+		return nil, false
+	}
+}
+
+// DecodeQuery decodes query strings such as a=1&b=2 into maps, the values of repeated parameters being slices
+func DecodeQuery() DeepDecoderFunc {
+	return func(s string) (interface{}, bool) {
+		s = strings.TrimPrefix(s, "?")
+		if !strings.Contains(s, "=") || strings.ContainsAny(s, " \t\r\n") {
+			return nil, false
+		}
+		values, err := url.ParseQuery(s)
+		if err != nil {
+			return nil, false
+		}
+		m := make(map[string]interface{}, len(values))
+		for k, v := range values {
+			if len(v) == 1 {
+				m[k] = v[0]
+			} else {
+				m[k] = v
+			}
+		}
+		return m, true
+	}
+}
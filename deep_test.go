@@ -0,0 +1,53 @@
+package dump_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestDeepDecoders(t *testing.T) {
+	type T struct {
+		Payload string
+		Token   string
+		Query   string
+		Other   string
+		JSON    string
+	}
+	a := T{
+		Payload: `<order id="42"><item>a</item><item>b</item><note lang="en">fragile</note></order>`,
+		Token:   base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"me","roles":["admin"]}`)),
+		Query:   "q=go+dump&page=2&tag=a&tag=b",
+		Other:   "a=b",
+		JSON:    `{"a": 1}`,
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.ExtraFields.DeepJSON = true
+	e.ExtraFields.DeepDecoders = []dump.DeepDecoder{
+		{Decode: dump.DecodeXML()},
+		{Decode: dump.DecodeBase64JSON(), Keys: []string{"T.Token"}},
+		{Decode: dump.DecodeQuery(), Keys: []string{"*.Query"}},
+	}
+	res, err := e.ToStringMap(a)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"T.Payload.order.@id":        "42",
+		"T.Payload.order.item.item0": "a",
+		"T.Payload.order.item.item1": "b",
+		"T.Payload.order.note.@lang": "en",
+		"T.Payload.order.note.#text": "fragile",
+		"T.Token.sub":                "me",
+		"T.Token.roles.roles0":       "admin",
+		"T.Query.q":                  "go dump",
+		"T.Query.page":               "2",
+		"T.Query.tag.tag0":           "a",
+		"T.Query.tag.tag1":           "b",
+		"T.Other":                    "a=b",
+		"T.JSON.a":                   "1",
+	}, res)
+}
@@ -5,8 +5,8 @@ import (
 	"os"
 )
 
-// Dump displays the passed parameter properties to standard out such as complete types and all
-// pointer addresses used to indirect to the final value.
+// Dump displays the passed parameter properties to standard out. Use an Encoder with ExtraFields.Type
+// and ExtraFields.Address to display complete types and all pointer addresses used to indirect to the final value.
 // See Fdump if you would prefer dumping to an arbitrary io.Writer or Sdump to
 // get the formatted result as a string.
 func Dump(i interface{}, formatters ...KeyFormatterFunc) error {
@@ -33,6 +33,16 @@ func Fdump(w io.Writer, i interface{}, formatters ...KeyFormatterFunc) error {
 	return e.Fdump(i)
 }
 
+// Tdump formats and displays the passed arguments to io.Writer w as an indented tree
+func Tdump(w io.Writer, i interface{}, formatters ...KeyFormatterFunc) error {
+	if formatters == nil {
+		formatters = []KeyFormatterFunc{WithDefaultFormatter()}
+	}
+	e := NewEncoder(w)
+	e.Formatters = formatters
+	return e.Tdump(i)
+}
+
 // ToMap dumps argument as a map[string]interface{}
 func ToMap(i interface{}, formatters ...KeyFormatterFunc) (map[string]interface{}, error) {
 	if formatters == nil {
@@ -43,6 +53,16 @@ func ToMap(i interface{}, formatters ...KeyFormatterFunc) (map[string]interface{
 	return e.ToMap(i)
 }
 
+// ToMapWithMeta dumps argument as a map[string]interface{} and returns the metadata of its keys separately
+func ToMapWithMeta(i interface{}, formatters ...KeyFormatterFunc) (map[string]interface{}, map[string]Meta, error) {
+	if formatters == nil {
+		formatters = []KeyFormatterFunc{WithDefaultFormatter()}
+	}
+	e := NewDefaultEncoder()
+	e.Formatters = formatters
+	return e.ToMapWithMeta(i)
+}
+
 // ToStringMap formats the argument as a map[string]string. It formats exactly the same as Dump.
 func ToStringMap(i interface{}, formatters ...KeyFormatterFunc) (map[string]string, error) {
 	if formatters == nil {
@@ -2,10 +2,13 @@ package dump_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -193,7 +196,7 @@ TM.C.bar.Tbis.__Type__: Tbis
 TM.C.foo.Tbis.Cbis: lol
 TM.C.foo.Tbis.Cter: lol
 TM.C.foo.Tbis.__Type__: Tbis
-__Type__: TM
+TM.__Type__: TM
 `
 	assert.Equal(t, expected, out.String())
 
@@ -301,7 +304,7 @@ TS.D.D0: true
 TS.D.D1: false
 TS.D.__Len__: 2
 TS.D.__Type__: Array
-__Type__: TS
+TS.__Type__: TS
 `
 	assert.Equal(t, expected, out.String())
 }
@@ -552,7 +555,7 @@ T.B: foo bar
 T.T: {"A":46,"B":"fiz buzz","T":null}
 T.T.A: 46
 T.T.B: fiz buzz
-T.T.T: 
+T.T.T:
 T.T.__Len__: 3
 T.__Len__: 3
 `, res)
@@ -602,6 +605,38 @@ func TestNoDumpJSONInString(t *testing.T) {
 	assert.Equal(t, "{ \"toctoc\": \"Qui est la\"}", m["t.b"])
 }
 
+func TestDumpJSONNumbersAndRawMessage(t *testing.T) {
+	type T struct {
+		Event   string
+		Payload json.RawMessage
+		Name    json.RawMessage
+	}
+	value := T{
+		Event:   `{"id": 12345678901234567890, "ratio": 0.10, "ids": [1e3]}`,
+		Payload: json.RawMessage(`{"id": 12345678901234567890, "tags": ["a"]}`),
+		Name:    json.RawMessage(`"foo"`),
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.ExtraFields.DeepJSON = true
+	m, err := e.ToMap(value)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"T.Event.id":           json.Number("12345678901234567890"),
+		"T.Event.ratio":        json.Number("0.10"),
+		"T.Event.ids.ids0":     json.Number("1e3"),
+		"T.Payload.id":         json.Number("12345678901234567890"),
+		"T.Payload.tags.tags0": "a",
+		"T.Name":               "foo",
+	}, m)
+
+	e.ExtraFields.DeepJSON = false
+	res, err := e.ToStringMap(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id": 12345678901234567890, "tags": ["a"]}`, res["T.Payload"])
+	assert.Equal(t, `"foo"`, res["T.Name"])
+}
+
 func TestBuildEnvironmentVariable(t *testing.T) {
 	type B struct {
 		PartOfB string
@@ -949,3 +984,365 @@ TT.DY: d
 `
 	assert.Equal(t, expected, out.String())
 }
+
+func TestIgnoreTypes(t *testing.T) {
+	type Service struct {
+		Name    string
+		Mu      sync.Mutex
+		Ctx     context.Context
+		Events  chan string
+		Handler func()
+		Clients map[string]*bytes.Buffer
+	}
+
+	s := Service{
+		Name:    "api",
+		Ctx:     context.Background(),
+		Events:  make(chan string),
+		Handler: func() {},
+		Clients: map[string]*bytes.Buffer{"main": bytes.NewBufferString("foo")},
+	}
+
+	out := &bytes.Buffer{}
+	e := dump.NewEncoder(out)
+	e.IgnoreTypes(sync.Mutex{}, (*context.Context)(nil), reflect.Func)
+	e.StubTypes("<chan>", reflect.Chan)
+	e.StubTypes("<buffer>", &bytes.Buffer{})
+	err := e.Fdump(&s)
+	assert.NoError(t, err)
+
+	expected := `Service.Clients.main: <buffer>
+Service.Events: <chan>
+Service.Name: api
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestIgnoreTypesOfNilFields(t *testing.T) {
+	type Service struct {
+		Name   string
+		Ctx    context.Context
+		Buffer *bytes.Buffer
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.IgnoreTypes((*context.Context)(nil))
+	e.StubTypes("<buffer>", &bytes.Buffer{})
+	res, err := e.ToStringMap(Service{Name: "api"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Service.Name":   "api",
+		"Service.Buffer": "<buffer>",
+	}, res)
+}
+
+func TestDumpAddressAndNil(t *testing.T) {
+	type Node struct {
+		Name string
+	}
+	type T struct {
+		A     *Node
+		B     *Node
+		C     *Node
+		D     string
+		Items []string
+		Attrs map[string]string
+	}
+
+	n := &Node{Name: "shared"}
+	a := T{A: n, B: n}
+
+	e := dump.NewDefaultEncoder()
+	e.ExtraFields.Address = true
+	e.ExtraFields.Nil = true
+	res, err := e.ToStringMap(a)
+	require.NoError(t, err)
+	t.Log(res)
+
+	require.Equal(t, fmt.Sprintf("%p", n), res["T.A.__Addr__"])
+	require.Equal(t, res["T.A.__Addr__"], res["T.B.__Addr__"])
+	require.Equal(t, "shared", res["T.B.Name"])
+	require.Equal(t, "", res["T.C"])
+	require.Equal(t, "true", res["T.C.__Nil__"])
+	require.Equal(t, "", res["T.D"])
+	require.NotContains(t, res, "T.D.__Nil__")
+	require.Equal(t, "true", res["T.Items.__Nil__"])
+	require.Equal(t, "true", res["T.Attrs.__Nil__"])
+}
+
+type Database struct {
+	Host string
+}
+
+func TestDumpDetailedType(t *testing.T) {
+	type T struct {
+		Backend interface{}
+		Ports   map[string][]int
+		Raw     []byte
+		Inline  struct {
+			Enabled bool
+		}
+	}
+
+	a := T{
+		Backend: &Database{Host: "localhost"},
+		Ports:   map[string][]int{"http": {80}},
+		Raw:     []byte("raw"),
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.ExtraFields.DetailedType = true
+	res, err := e.ToStringMap(a)
+	require.NoError(t, err)
+	t.Log(res)
+
+	require.Equal(t, "github.com/fsamin/go-dump_test.T", res["T.__Type__"])
+	require.Equal(t, "struct", res["T.__Kind__"])
+	require.Equal(t, "*github.com/fsamin/go-dump_test.Database", res["T.Backend.__Type__"])
+	require.Equal(t, "struct", res["T.Backend.__Kind__"])
+	require.Equal(t, "string", res["T.Backend.Host.__Type__"])
+	require.Equal(t, "map[string][]int", res["T.Ports.__Type__"])
+	require.Equal(t, "map", res["T.Ports.__Kind__"])
+	require.Equal(t, "[]int", res["T.Ports.http.__Type__"])
+	require.Equal(t, "int", res["T.Ports.http.http0.__Type__"])
+	require.Equal(t, "[]uint8", res["T.Raw.__Type__"])
+	require.Equal(t, "struct { Enabled bool }", res["T.Inline.__Type__"])
+}
+
+func TestToMapWithMeta(t *testing.T) {
+	type T struct {
+		A  int
+		B  []string
+		C  map[string]Tbis
+		DB *Database
+	}
+
+	a := T{A: 23, B: []string{"foo", "bar"}}
+
+	e := dump.NewDefaultEncoder()
+	e.ExtraFields.Len = true
+	e.ExtraFields.Type = true
+	e.Prefix = "test"
+	data, meta, err := e.ToMapWithMeta(a)
+	require.NoError(t, err)
+	t.Log(data, meta)
+
+	require.Equal(t, map[string]interface{}{
+		"test.T.A":    23,
+		"test.T.B.B0": "foo",
+		"test.T.B.B1": "bar",
+		"test.T.DB":   "",
+	}, data)
+
+	require.Equal(t, dump.Meta{Len: 4, Type: "github.com/fsamin/go-dump_test.T", Kind: reflect.Struct}, meta["test.T"])
+	require.Equal(t, dump.Meta{Type: "int", Kind: reflect.Int, Field: "A"}, meta["test.T.A"])
+	require.Equal(t, dump.Meta{Len: 2, Type: "[]string", Kind: reflect.Slice, Field: "B"}, meta["test.T.B"])
+	require.Equal(t, dump.Meta{Type: "string", Kind: reflect.String}, meta["test.T.B.B1"])
+	require.Equal(t, dump.Meta{Type: "map[string]github.com/fsamin/go-dump_test.Tbis", Kind: reflect.Map, Nil: true, Field: "C"}, meta["test.T.C"])
+	require.Equal(t, dump.Meta{Type: "*github.com/fsamin/go-dump_test.Database", Kind: reflect.Ptr, Nil: true, Field: "DB"}, meta["test.T.DB"])
+}
+
+func TestMetaKeysWithPrefix(t *testing.T) {
+	type MyStruct struct {
+		A string
+		B []string
+	}
+
+	a := MyStruct{A: "value A", B: []string{"b1", "b2"}}
+
+	out := &bytes.Buffer{}
+	dumper := dump.NewEncoder(out)
+	dumper.DisableTypePrefix = true
+	dumper.Separator = "_"
+	dumper.Prefix = "MYSTRUCT"
+	dumper.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultUpperCaseFormatter()}
+	dumper.ExtraFields.Len = true
+	dumper.ExtraFields.Type = true
+	dumper.MetaKeys.Len = "Count"
+	err := dumper.Fdump(a)
+	assert.NoError(t, err)
+
+	expected := `MYSTRUCT_A: value A
+MYSTRUCT_B_B0: b1
+MYSTRUCT_B_B1: b2
+MYSTRUCT_B_COUNT: 2
+MYSTRUCT_B___TYPE__: Array
+MYSTRUCT___TYPE__: MyStruct
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestMetaKeysDefaults(t *testing.T) {
+	type T struct {
+		B []string
+	}
+
+	e := &dump.Encoder{Separator: "."}
+	e.ExtraFields.Len = true
+	e.ExtraFields.Type = true
+	res, err := e.ToStringMap(T{B: []string{"b1"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"T.B.B0":       "b1",
+		"T.B.__Len__":  "1",
+		"T.B.__Type__": "Array",
+		"T.__Type__":   "T",
+	}, res)
+}
+
+func TestMetaKeysOfTopLevelStruct(t *testing.T) {
+	type T struct {
+		A string
+		B []string
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.Prefix = "APP"
+	e.ExtraFields.Len = true
+	e.ExtraFields.Type = true
+	e.ExtraFields.DetailedStruct = true
+	res, err := e.ToStringMap(T{A: "a", B: []string{"b"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"APP.T.A":          "a",
+		"APP.T.B.B0":       "b",
+		"APP.T.B.__Len__":  "1",
+		"APP.T.B.__Type__": "Array",
+		"APP.T.__Len__":    "2",
+		"APP.T.__Type__":   "T",
+	}, res)
+
+	e.ExtraFields.Type = false
+	e.ExtraFields.DetailedType = true
+	e.ExtraFields.Address = true
+	res, err = e.ToStringMap(&T{A: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, "*github.com/fsamin/go-dump_test.T", res["APP.T.__Type__"])
+	assert.Equal(t, "struct", res["APP.T.__Kind__"])
+	assert.NotEmpty(t, res["APP.T.__Addr__"])
+	for k := range res {
+		assert.True(t, strings.HasPrefix(k, "APP.T."), k)
+	}
+}
+
+func TestDumpLineFormat(t *testing.T) {
+	type T struct {
+		A    int
+		Long string
+		C    string
+	}
+
+	a := T{A: 23, Long: "foo bar"}
+
+	out := &bytes.Buffer{}
+	e := dump.NewEncoder(out)
+	e.AlignKeys = true
+	err := e.Fdump(a)
+	assert.NoError(t, err)
+
+	expected := `T.A   : 23
+T.C   :
+T.Long: foo bar
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	e.LineFormatter = dump.WithLineFormat("%s = %s\n")
+	err = e.Fdump(a)
+	assert.NoError(t, err)
+
+	expected = `T.A    = 23
+T.C    = 
+T.Long = foo bar
+`
+	assert.Equal(t, expected, out.String())
+
+	e.AlignKeys = false
+	e.LineFormatter = dump.WithLineFormat("%s\t%s\n")
+	res, err := e.Sdump(a)
+	assert.NoError(t, err)
+	assert.Equal(t, "T.A\t23\nT.C\t\nT.Long\tfoo bar\n", res)
+}
+
+func TestFiltersAndRedaction(t *testing.T) {
+	type Credentials struct {
+		User     string
+		Password string
+	}
+	type T struct {
+		Name  string
+		DB    Database
+		Creds Credentials `dump:"secret"`
+		Token string      `dump:"secret"`
+		Tags  []string
+	}
+
+	a := T{Name: "api", DB: Database{Host: "db"}, Creds: Credentials{User: "u", Password: "p"}, Token: "t", Tags: []string{"a", "b"}}
+
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.Redaction = "***"
+	e.Filters = []string{"DB", "Creds", "Token", "Tags*"}
+	res, err := e.ToStringMap(a)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DB.Host":    "db",
+		"Creds":      "***",
+		"Token":      "***",
+		"Tags.Tags0": "a",
+		"Tags.Tags1": "b",
+	}, res)
+
+	e.Filters = []string{"Tags.*1"}
+	data, meta, err := e.ToMapWithMeta(a)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Tags.Tags1": "b"}, data)
+	assert.Len(t, meta, 3)
+	assert.Contains(t, meta, "")
+	assert.Contains(t, meta, "Tags")
+
+	e.Filters = []string{"["}
+	_, err = e.ToMap(a)
+	assert.Error(t, err)
+}
+
+func TestRedactionOfDetailedValues(t *testing.T) {
+	type Credentials struct {
+		User     string
+		Password string `dump:"secret"`
+	}
+	type T struct {
+		Creds  Credentials
+		Pool   []Credentials
+		Others map[string]Credentials
+		DB     Database
+	}
+
+	a := T{
+		Creds:  Credentials{User: "u", Password: "p"},
+		Pool:   []Credentials{{User: "v", Password: "q"}},
+		Others: map[string]Credentials{"main": {User: "w", Password: "r"}},
+		DB:     Database{Host: "db"},
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.Redaction = "***"
+	e.ExtraFields.DetailedStruct = true
+	e.ExtraFields.DetailedMap = true
+	e.ExtraFields.DetailedArray = true
+	res, err := e.ToStringMap(a)
+	require.NoError(t, err)
+	assert.Equal(t, "***", res["T.Creds"])
+	assert.Equal(t, "***", res["T.Pool"])
+	assert.Equal(t, "***", res["T.Pool.Pool0"])
+	assert.Equal(t, "***", res["T.Others"])
+	assert.Equal(t, "***", res["T.Others.main.Credentials"])
+	assert.Equal(t, "u", res["T.Creds.User"])
+	assert.Equal(t, "***", res["T.Creds.Password"])
+	assert.Equal(t, `{"Host":"db"}`, res["T.DB"])
+	for k, v := range res {
+		assert.NotContains(t, v, `"p"`, k)
+		assert.NotContains(t, v, `"q"`, k)
+		assert.NotContains(t, v, `"r"`, k)
+	}
+}
@@ -0,0 +1,41 @@
+// Package dumpdecode provides deep decoders for the YAML and TOML formats, to be used with the
+// ExtraFields.DeepDecoders option of the go-dump encoder.
+package dumpdecode
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fsamin/go-dump"
+)
+
+// YAML decodes YAML mappings and sequences. Other YAML documents, such as plain strings, are not decoded.
+func YAML() dump.DeepDecoderFunc {
+	return func(s string) (interface{}, bool) {
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+			return nil, false
+		}
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return v, true
+		}
+		return nil, false
+	}
+}
+
+// TOML decodes TOML documents holding at least a key
+func TOML() dump.DeepDecoderFunc {
+	return func(s string) (interface{}, bool) {
+		if !strings.Contains(s, "=") {
+			return nil, false
+		}
+		v := map[string]interface{}{}
+		if _, err := toml.Decode(s, &v); err != nil || len(v) == 0 {
+			return nil, false
+		}
+		return v, true
+	}
+}
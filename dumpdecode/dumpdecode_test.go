@@ -0,0 +1,40 @@
+package dumpdecode_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fsamin/go-dump"
+	"github.com/fsamin/go-dump/dumpdecode"
+)
+
+func TestDecoders(t *testing.T) {
+	type T struct {
+		Settings string
+		Config   string
+		Note     string
+	}
+	a := T{
+		Settings: "retries: 3\nhosts:\n  - a\n  - b\n",
+		Config:   "[server]\nport = 8080\n",
+		Note:     "plain text",
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.ExtraFields.DeepDecoders = []dump.DeepDecoder{
+		{Decode: dumpdecode.YAML(), Keys: []string{"Settings", "Note"}},
+		{Decode: dumpdecode.TOML()},
+	}
+	res, err := e.ToStringMap(a)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Settings.retries":      "3",
+		"Settings.hosts.hosts0": "a",
+		"Settings.hosts.hosts1": "b",
+		"Config.server.port":    "8080",
+		"Note":                  "plain text",
+	}, res)
+}
@@ -0,0 +1,10 @@
+module github.com/fsamin/go-dump/dumpdecode
+
+go 1.15
+
+require (
+	github.com/BurntSushi/toml v0.4.1
+	github.com/fsamin/go-dump v0.0.0-20261018221523-7143e5a4d860
+	github.com/stretchr/testify v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
+)
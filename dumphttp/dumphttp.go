@@ -0,0 +1,138 @@
+// Package dumphttp serves live dumps of registered objects over HTTP, in the manner of expvar.
+//
+// Importing the package registers its handler on http.DefaultServeMux under /debug/dump/. Objects registered
+// with Register are then rendered at /debug/dump/<name>, and /debug/dump/ lists their names.
+// The query parameters of the requests select the rendering:
+//
+//	format   text (default), env, json or tree
+//	filter   keys to render, with the syntax of path.Match, as rendered in the format; it may be repeated
+//
+// Objects are dumped on each request, register pointers to serve up to date values.
+package dumphttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsamin/go-dump"
+)
+
+var (
+	mutex   sync.RWMutex
+	objects = map[string]interface{}{}
+)
+
+// NewEncoder returns the encoder used to render the registered objects over w. It can be replaced to change
+// the options of the encoder, the format of the request then sets the separator, the formatters and the lines.
+// The default encoder has no type prefix and redacts the fields tagged `dump:"secret"`.
+var NewEncoder = func(w io.Writer) *dump.Encoder {
+	e := dump.NewEncoder(w)
+	e.DisableTypePrefix = true
+	e.Redaction = "********"
+	return e
+}
+
+func init() {
+	http.Handle("/debug/dump/", Handler())
+}
+
+// Register registers the object i under name. It panics if the name is already registered.
+func Register(name string, i interface{}) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if _, ok := objects[name]; ok {
+		panic(fmt.Sprintf("dumphttp: reuse of registered name %q", name))
+	}
+	objects[name] = i
+}
+
+// Unregister removes the object registered under name, if any
+func Unregister(name string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(objects, name)
+}
+
+// Handler returns the handler serving the registered objects, which is registered on http.DefaultServeMux
+// under /debug/dump/
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path
+	if i := strings.LastIndex(name, "/debug/dump/"); i >= 0 {
+		name = name[i+len("/debug/dump/"):]
+	}
+	name = strings.Trim(name, "/")
+
+	mutex.RLock()
+	if name == "" {
+		names := make([]string, 0, len(objects))
+		for n := range objects {
+			names = append(names, n)
+		}
+		mutex.RUnlock()
+		sort.Strings(names)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, n := range names {
+			fmt.Fprintln(w, n)
+		}
+		return
+	}
+	i, ok := objects[name]
+	mutex.RUnlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("no object registered as %q", name), http.StatusNotFound)
+		return
+	}
+
+	filters := r.URL.Query()["filter"]
+	for _, f := range filters {
+		if _, err := path.Match(f, ""); err != nil {
+			http.Error(w, fmt.Sprintf("invalid filter %q: %v", f, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	e := NewEncoder(buf)
+	e.Filters = filters
+	var err error
+	contentType := "text/plain; charset=utf-8"
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "text":
+		err = e.Fdump(i)
+	case "env":
+		e.Separator = "_"
+		e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultUpperCaseFormatter()}
+		e.LineFormatter = dump.WithLineFormat("%s=%s\n")
+		err = e.Fdump(i)
+	case "json":
+		var m map[string]interface{}
+		if m, err = e.ToMap(i); err == nil {
+			enc := json.NewEncoder(buf)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(m)
+		}
+		contentType = "application/json"
+	case "tree":
+		err = e.Tdump(i)
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q", format), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	buf.WriteTo(w)
+}
@@ -0,0 +1,82 @@
+package dumphttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fsamin/go-dump/dumphttp"
+)
+
+type database struct {
+	Host     string
+	Port     int
+	Password string `dump:"secret"`
+}
+
+type config struct {
+	Name     string
+	Database database
+}
+
+func get(t *testing.T, url string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	var cfg config
+	cfg.Name = "api"
+	cfg.Database.Host = "db"
+	cfg.Database.Port = 5432
+	cfg.Database.Password = "s3cr3t"
+	dumphttp.Register("config", &cfg)
+	defer dumphttp.Unregister("config")
+
+	rec := get(t, "/debug/dump/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "config\n", rec.Body.String())
+
+	rec = get(t, "/debug/dump/config")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `Database.Host: db
+Database.Password: ********
+Database.Port: 5432
+Name: api
+`, rec.Body.String())
+
+	cfg.Database.Port = 5433
+	rec = get(t, "/debug/dump/config?format=env&filter=DATABASE_*")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `DATABASE_HOST=db
+DATABASE_PASSWORD=********
+DATABASE_PORT=5433
+`, rec.Body.String())
+
+	rec = get(t, "/debug/dump/config?format=json&filter=Name")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"Name": "api"}`, rec.Body.String())
+
+	rec = get(t, "/debug/dump/config?format=tree&filter=Database")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `Database: (dumphttp_test.database)
+  Host: (string) db
+  Password: (string) ********
+  Port: (int) 5433
+`, rec.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, get(t, "/debug/dump/unknown").Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, "/debug/dump/config?format=xml").Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, "/debug/dump/config?filter=[").Code)
+}
+
+func TestRegisterTwice(t *testing.T) {
+	dumphttp.Register("twice", 1)
+	defer dumphttp.Unregister("twice")
+	assert.Panics(t, func() { dumphttp.Register("twice", 2) })
+}
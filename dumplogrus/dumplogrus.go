@@ -0,0 +1,31 @@
+// Package dumplogrus turns the keys and values dumped by go-dump into logrus fields.
+package dumplogrus
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/fsamin/go-dump"
+)
+
+// Fields returns the dumped keys and values of i as logrus fields. The values keep their type.
+// i is dumped with e, or with the default encoder if e is nil.
+func Fields(e *dump.Encoder, i interface{}) (logrus.Fields, error) {
+	if e == nil {
+		e = dump.NewDefaultEncoder()
+	}
+	res, err := e.ToMap(i)
+	if err != nil {
+		return nil, err
+	}
+	return logrus.Fields(res), nil
+}
+
+// WithFields returns an entry of l with the dumped keys and values of i as fields,
+// or with the error if i cannot be dumped
+func WithFields(l logrus.FieldLogger, e *dump.Encoder, i interface{}) *logrus.Entry {
+	fields, err := Fields(e, i)
+	if err != nil {
+		return l.WithError(err)
+	}
+	return l.WithFields(fields)
+}
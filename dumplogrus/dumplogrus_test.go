@@ -0,0 +1,41 @@
+package dumplogrus_test
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fsamin/go-dump"
+	"github.com/fsamin/go-dump/dumplogrus"
+)
+
+type config struct {
+	Name     string
+	Port     int
+	Password string `dump:"secret"`
+}
+
+func TestFields(t *testing.T) {
+	cfg := config{Name: "api", Port: 8080, Password: "s3cr3t"}
+
+	e := dump.NewDefaultEncoder()
+	e.Separator = "_"
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	e.Redaction = "********"
+
+	fields, err := dumplogrus.Fields(e, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, logrus.Fields{
+		"config_name":     "api",
+		"config_port":     8080,
+		"config_password": "********",
+	}, fields)
+
+	logger, hook := test.NewNullLogger()
+	dumplogrus.WithFields(logger, e, &cfg).Info("starting")
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, fields, hook.LastEntry().Data)
+}
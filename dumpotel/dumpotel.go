@@ -0,0 +1,213 @@
+// Package dumpotel turns the keys and values dumped by go-dump into OpenTelemetry attributes.
+package dumpotel
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/fsamin/go-dump"
+)
+
+// Attributes returns the dumped keys and values of i as attributes sorted by key, keeping at most limit
+// attributes if limit is positive. i is dumped with e, or with the default encoder if e is nil.
+// Booleans, integers, floats and strings keep their type, other values are written as strings.
+// Arrays and slices of booleans, integers, floats or strings, except the top level one, are written as a single
+// slice attribute rather than as a key per item.
+func Attributes(e *dump.Encoder, i interface{}, limit int) ([]attribute.KeyValue, error) {
+	if e == nil {
+		e = dump.NewDefaultEncoder()
+	}
+	data, meta, err := e.ToMapWithMeta(i)
+	if err != nil {
+		return nil, err
+	}
+
+	// The keys are grouped by parent once, so that each array only looks at its own items
+	children := map[string][]string{}
+	for k := range meta {
+		children[parentKey(e, k)] = append(children[parentKey(e, k)], k)
+	}
+	for k := range data {
+		if _, ok := meta[k]; !ok {
+			children[parentKey(e, k)] = append(children[parentKey(e, k)], k)
+		}
+	}
+
+	var attrs []attribute.KeyValue
+	items := map[string]bool{}
+	for k, m := range meta {
+		if k == "" || m.Nil || m.Len == 0 || m.Kind != reflect.Array && m.Kind != reflect.Slice {
+			continue
+		}
+		attr, keys, ok := sliceAttribute(e, k, m.Len, data, children)
+		if !ok {
+			continue
+		}
+		attrs = append(attrs, attr)
+		for _, key := range keys {
+			items[key] = true
+		}
+	}
+	for k, v := range data {
+		if !items[k] {
+			attrs = append(attrs, value(k, v))
+		}
+	}
+
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	if limit > 0 && len(attrs) > limit {
+		attrs = attrs[:limit]
+	}
+	return attrs, nil
+}
+
+// sliceAttribute returns the slice attribute of the array k and the keys of its items, if its n items are
+// values of the same type. children are the keys grouped by parent.
+func sliceAttribute(e *dump.Encoder, k string, n int, data map[string]interface{}, children map[string][]string) (attribute.KeyValue, []string, bool) {
+	keys := make([]string, n)
+	values := make([]attribute.Value, n)
+	for _, key := range children[k] {
+		idx, item := itemIndex(e, k, key)
+		if !item || idx >= n || keys[idx] != "" || len(children[key]) > 0 {
+			return attribute.KeyValue{}, nil, false
+		}
+		v, ok := data[key]
+		if !ok {
+			return attribute.KeyValue{}, nil, false
+		}
+		keys[idx] = key
+		values[idx] = value(key, v).Value
+	}
+	for _, key := range keys {
+		if key == "" {
+			return attribute.KeyValue{}, nil, false
+		}
+	}
+
+	key := attribute.Key(k)
+	switch values[0].Type() {
+	case attribute.BOOL:
+		s := make([]bool, n)
+		for i, v := range values {
+			if v.Type() != attribute.BOOL {
+				return attribute.KeyValue{}, nil, false
+			}
+			s[i] = v.AsBool()
+		}
+		return key.BoolSlice(s), keys, true
+	case attribute.INT64:
+		s := make([]int64, n)
+		for i, v := range values {
+			if v.Type() != attribute.INT64 {
+				return attribute.KeyValue{}, nil, false
+			}
+			s[i] = v.AsInt64()
+		}
+		return key.Int64Slice(s), keys, true
+	case attribute.FLOAT64:
+		s := make([]float64, n)
+		for i, v := range values {
+			if v.Type() != attribute.FLOAT64 {
+				return attribute.KeyValue{}, nil, false
+			}
+			s[i] = v.AsFloat64()
+		}
+		return key.Float64Slice(s), keys, true
+	case attribute.STRING:
+		s := make([]string, n)
+		for i, v := range values {
+			if v.Type() != attribute.STRING {
+				return attribute.KeyValue{}, nil, false
+			}
+			s[i] = v.AsString()
+		}
+		return key.StringSlice(s), keys, true
+	}
+	return attribute.KeyValue{}, nil, false
+}
+
+// parentKey returns the key which key is directly nested under: the array of an item written with
+// ArrayJSONNotation, or the key before the last separator
+func parentKey(e *dump.Encoder, key string) string {
+	if e.ArrayJSONNotation && strings.HasSuffix(key, "]") {
+		if i := strings.LastIndex(key, "["); i > 0 {
+			return key[:i]
+		}
+	}
+	if e.Separator == "" {
+		return ""
+	}
+	if i := strings.LastIndex(key, e.Separator); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+// itemIndex returns the index of key if it is an item of the array k, key being directly nested under k
+func itemIndex(e *dump.Encoder, k, key string) (int, bool) {
+	var digits string
+	switch rest := key[len(k):]; {
+	case e.ArrayJSONNotation && strings.HasPrefix(rest, "[") && strings.HasSuffix(rest, "]"):
+		digits = rest[1 : len(rest)-1]
+	case e.Separator != "" && strings.HasPrefix(rest, e.Separator):
+		// The items are labelled as fDumpArray does, with the last segment of k followed by the index
+		label := k
+		if i := strings.LastIndex(k, e.Separator); i >= 0 {
+			label = k[i+len(e.Separator):]
+		}
+		if item := rest[len(e.Separator):]; strings.HasPrefix(item, label) {
+			digits = item[len(label):]
+		}
+	}
+	idx, err := strconv.Atoi(digits)
+	if err != nil || idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+// value returns the attribute of the value v, typed after the kind of v
+func value(k string, v interface{}) attribute.KeyValue {
+	key := attribute.Key(k)
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return key.Int64(i)
+		}
+		if f, err := t.Float64(); err == nil {
+			return key.Float64(f)
+		}
+		return key.String(t.String())
+	case fmt.Stringer:
+		return key.String(t.String())
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return key.Bool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return key.Int64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return key.Int64(int64(u))
+		}
+		return key.String(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32:
+		return key.Float64(dump.Float32Value(float32(rv.Float())))
+	case reflect.Float64:
+		return key.Float64(rv.Float())
+	case reflect.String:
+		return key.String(rv.String())
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return key.String(string(b))
+	}
+	return key.String(fmt.Sprintf("%v", v))
+}
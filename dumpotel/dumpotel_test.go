@@ -0,0 +1,93 @@
+package dumpotel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/fsamin/go-dump"
+	"github.com/fsamin/go-dump/dumpotel"
+)
+
+type item struct {
+	Name string
+}
+
+type job struct {
+	ID      uint64
+	Name    string
+	Retry   bool
+	Ratio   float32
+	Timeout time.Duration
+	Tags    []string
+	Weights []float64
+	Mixed   []interface{}
+	Items   []item
+	Token   string `dump:"secret"`
+}
+
+func TestAttributes(t *testing.T) {
+	j := job{
+		ID:      42,
+		Name:    "build",
+		Retry:   true,
+		Ratio:   0.1,
+		Timeout: time.Minute,
+		Tags:    []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+		Weights: []float64{1, 2.5},
+		Mixed:   []interface{}{1, "one"},
+		Items:   []item{{Name: "x"}},
+		Token:   "t",
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.Redaction = "***"
+	attrs, err := dumpotel.Attributes(e, &j, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int64("ID", 42),
+		attribute.String("Items.Items0.Name", "x"),
+		attribute.Int64("Mixed.Mixed0", 1),
+		attribute.String("Mixed.Mixed1", "one"),
+		attribute.String("Name", "build"),
+		attribute.Float64("Ratio", 0.1),
+		attribute.Bool("Retry", true),
+		attribute.StringSlice("Tags", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}),
+		attribute.String("Timeout", "1m0s"),
+		attribute.String("Token", "***"),
+		attribute.Float64Slice("Weights", []float64{1, 2.5}),
+	}, attrs)
+
+	e.ArrayJSONNotation = true
+	attrs, err = dumpotel.Attributes(e, &j, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int64("ID", 42),
+		attribute.String("Items[0].Name", "x"),
+		attribute.Int64("Mixed[0]", 1),
+	}, attrs)
+
+	attrs, err = dumpotel.Attributes(nil, []int{1, 2}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("0", 1), attribute.Int64("1", 2)}, attrs)
+}
+
+func TestAttributesOfArraysNamedWithDigits(t *testing.T) {
+	type T struct {
+		S3Buckets []string
+		V2        []int
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	attrs, err := dumpotel.Attributes(e, T{S3Buckets: []string{"a", "b"}, V2: []int{1, 2, 3}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("S3Buckets", []string{"a", "b"}),
+		attribute.Int64Slice("V2", []int64{1, 2, 3}),
+	}, attrs)
+}
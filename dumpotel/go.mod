@@ -0,0 +1,9 @@
+module github.com/fsamin/go-dump/dumpotel
+
+go 1.15
+
+require (
+	github.com/fsamin/go-dump v0.0.0-20261018221523-7143e5a4d860
+	github.com/stretchr/testify v1.8.1
+	go.opentelemetry.io/otel v1.2.0
+)
@@ -0,0 +1,57 @@
+// Package dumpzap turns the keys and values dumped by go-dump into zap fields.
+package dumpzap
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/fsamin/go-dump"
+)
+
+// Marshaler is a zapcore.ObjectMarshaler adding the dumped keys and values of Value, sorted by key,
+// to the object encoder. Value is dumped with Encoder, or with the default encoder if Encoder is nil.
+type Marshaler struct {
+	Encoder *dump.Encoder
+	Value   interface{}
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (m Marshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	fields, err := Fields(m.Encoder, m.Value)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// Object returns a field holding the dumped keys and values of i as an object named key
+func Object(key string, e *dump.Encoder, i interface{}) zap.Field {
+	return zap.Object(key, Marshaler{Encoder: e, Value: i})
+}
+
+// Fields returns the dumped keys and values of i as fields sorted by key. The values keep their type.
+// i is dumped with e, or with the default encoder if e is nil.
+func Fields(e *dump.Encoder, i interface{}) ([]zap.Field, error) {
+	if e == nil {
+		e = dump.NewDefaultEncoder()
+	}
+	res, err := e.ToMap(i)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(res))
+	for k := range res {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, zap.Any(k, res[k]))
+	}
+	return fields, nil
+}
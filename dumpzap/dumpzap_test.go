@@ -0,0 +1,48 @@
+package dumpzap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/fsamin/go-dump"
+	"github.com/fsamin/go-dump/dumpzap"
+)
+
+type config struct {
+	Name     string
+	Port     int
+	Password string `dump:"secret"`
+}
+
+func TestFields(t *testing.T) {
+	cfg := config{Name: "api", Port: 8080, Password: "s3cr3t"}
+
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	e.Redaction = "********"
+
+	fields, err := dumpzap.Fields(e, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []zap.Field{
+		zap.String("name", "api"),
+		zap.String("password", "********"),
+		zap.Int("port", 8080),
+	}, fields)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	zap.New(core).Info("starting", dumpzap.Object("config", e, &cfg))
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, map[string]interface{}{
+		"config": map[string]interface{}{
+			"name":     "api",
+			"password": "********",
+			"port":     int64(8080),
+		},
+	}, logs.All()[0].ContextMap())
+}
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path"
 	"reflect"
 	"runtime"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // Encoder ensures all options to dump an object
@@ -22,12 +24,82 @@ type Encoder struct {
 		DetailedArray  bool
 		DeepJSON       bool
 		UseJSONTag     bool
-	}
+		Address        bool
+		Nil            bool
+		DetailedType   bool
+		// DeepDecoders decode the string values, after JSON if DeepJSON is set, to dump their content
+		DeepDecoders []DeepDecoder
+	}
+	// MetaKeys are the names of the metadata keys, formatted, prefixed and separated as data keys
+	MetaKeys struct {
+		Len  string
+		Type string
+		Kind string
+		Addr string
+		Nil  string
+	}
+	// LineFormatter formats the lines written by Fdump and Sdump
+	LineFormatter LineFormatterFunc
+	// AlignKeys pads the keys written by Fdump and Sdump to a common width
+	AlignKeys bool
+	// Filters restricts the dumped keys to the keys matching one of the patterns, with the syntax of path.Match,
+	// and to the keys nested under them
+	Filters []string
+	// Redaction replaces the values of the fields tagged `dump:"secret"`, and the detailed values holding them, when it is not empty
+	Redaction         string
 	ArrayJSONNotation bool
 	Separator         string
 	DisableTypePrefix bool
 	Prefix            string
 	writer            io.Writer
+	typeRules         []typeRule
+}
+
+// Meta holds the metadata of a key produced by the traversal
+type Meta struct {
+	// Len is the number of items of an array or a map, or the number of fields of a struct
+	Len int
+	// Type is the fully qualified type of the value, such as *github.com/acme/cfg.Database
+	Type string
+	// Kind is the kind of the value once pointers and interfaces are dereferenced
+	Kind reflect.Kind
+	// Nil is true for nil pointers, interfaces, slices and maps
+	Nil bool
+	// Addr holds the addresses of the pointers followed to reach the value
+	Addr string
+	// Field is the name of the struct field the value comes from
+	Field string
+	// Secret is true for the values of the fields tagged `dump:"secret"` and their content
+	Secret bool
+}
+
+// result gathers the values, and the metadata when they are requested, produced by the traversal
+type result struct {
+	data map[string]interface{}
+	meta map[string]Meta
+	// secret counts the secret fields being dumped
+	secret int
+	// redacted counts the secret fields whose value has been replaced by the Redaction
+	redacted int
+}
+
+// typeRule matches the types registered with IgnoreTypes and StubTypes
+type typeRule struct {
+	kind        reflect.Kind
+	typ         reflect.Type
+	stub        bool
+	placeholder string
+}
+
+func (r typeRule) match(t reflect.Type) bool {
+	switch {
+	case r.typ == nil:
+		return t.Kind() == r.kind
+	case r.typ.Kind() == reflect.Interface:
+		return t.Implements(r.typ)
+	default:
+		return t == r.typ
+	}
 }
 
 // NewDefaultEncoder instanciate a go-dump encoder
@@ -41,126 +113,325 @@ func NewEncoder(w io.Writer) *Encoder {
 		Formatters: []KeyFormatterFunc{
 			WithDefaultFormatter(),
 		},
-		Separator: ".",
-		writer:    w,
+		LineFormatter: WithDefaultLineFormatter(),
+		Separator:     ".",
+		writer:        w,
 	}
+	enc.MetaKeys = enc.metaKeys()
 	return enc
 }
 
+// metaKeys returns the MetaKeys, with the default names in place of the empty ones
+func (e *Encoder) metaKeys() (names struct{ Len, Type, Kind, Addr, Nil string }) {
+	names = e.MetaKeys
+	if names.Len == "" {
+		names.Len = "__Len__"
+	}
+	if names.Type == "" {
+		names.Type = "__Type__"
+	}
+	if names.Kind == "" {
+		names.Kind = "__Kind__"
+	}
+	if names.Addr == "" {
+		names.Addr = "__Addr__"
+	}
+	if names.Nil == "" {
+		names.Nil = "__Nil__"
+	}
+	return names
+}
+
+// IgnoreTypes registers types which are skipped during the traversal. Each argument is either a reflect.Type,
+// a reflect.Kind (such as reflect.Chan or reflect.Func) matching every type of this kind, or a value of the type
+// to ignore. A nil pointer to an interface, such as (*context.Context)(nil), stands for the interface type and
+// matches every value implementing it. Pointers to an ignored type are ignored as well.
+func (e *Encoder) IgnoreTypes(types ...interface{}) {
+	e.addTypeRules(false, "", types)
+}
+
+// StubTypes registers types which are not traversed but rendered as the placeholder value.
+// Types are passed the same way as for IgnoreTypes.
+func (e *Encoder) StubTypes(placeholder string, types ...interface{}) {
+	e.addTypeRules(true, placeholder, types)
+}
+
+func (e *Encoder) addTypeRules(stub bool, placeholder string, types []interface{}) {
+	for _, t := range types {
+		r := typeRule{stub: stub, placeholder: placeholder}
+		switch v := t.(type) {
+		case reflect.Kind:
+			r.kind = v
+		case reflect.Type:
+			r.typ = v
+		default:
+			r.typ = reflect.TypeOf(t)
+			if r.typ == nil {
+				continue
+			}
+			if r.typ.Kind() == reflect.Ptr && r.typ.Elem().Kind() == reflect.Interface {
+				r.typ = r.typ.Elem()
+			}
+		}
+		e.typeRules = append(e.typeRules, r)
+	}
+}
+
+// typeRule returns the rule registered for the type of i, if any
+func (e *Encoder) typeRule(i interface{}) (typeRule, bool) {
+	return e.typeRuleOf(reflect.TypeOf(i))
+}
+
+// typeRuleOf returns the rule registered for the type t, if any
+func (e *Encoder) typeRuleOf(t reflect.Type) (typeRule, bool) {
+	if t == nil {
+		return typeRule{}, false
+	}
+	for _, r := range e.typeRules {
+		if r.match(t) || t.Kind() == reflect.Ptr && r.match(t.Elem()) {
+			return r, true
+		}
+	}
+	return typeRule{}, false
+}
+
 // Fdump formats and displays the passed arguments to io.Writer w. It formats exactly the same as Dump.
 func (e *Encoder) Fdump(i interface{}) (err error) {
 	res, err := e.ToStringMap(i)
 	if err != nil {
 		return
 	}
+	return e.fdumpLines(e.writer, res)
+}
+
+// Sdump returns a string with the passed arguments formatted exactly the same as Dump.
+func (e *Encoder) Sdump(i interface{}) (string, error) {
+	m, err := e.ToStringMap(i)
+	if err != nil {
+		return "", err
+	}
+	res := new(bytes.Buffer)
+	if err := e.fdumpLines(res, m); err != nil {
+		return "", err
+	}
+	return res.String(), nil
+}
+
+// fdumpLines writes the sorted keys and values of res with the LineFormatter, keys are padded if AlignKeys is set
+func (e *Encoder) fdumpLines(w io.Writer, res map[string]string) error {
+	lineFormatter := e.LineFormatter
+	if lineFormatter == nil {
+		lineFormatter = WithDefaultLineFormatter()
+	}
 
 	keys := []string{}
+	var width int
 	for k := range res {
 		keys = append(keys, k)
+		if l := utf8.RuneCountInString(k); l > width {
+			width = l
+		}
 	}
 	sort.Strings(keys)
 	for _, k := range keys {
-		var err error
-		if res[k] == "" {
-			_, err = fmt.Fprintf(e.writer, "%s:\n", k)
-		} else {
-			_, err = fmt.Fprintf(e.writer, "%s: %s\n", k, res[k])
+		key := k
+		if e.AlignKeys {
+			key = pad(k, width)
 		}
-		if err != nil {
+		if _, err := io.WriteString(w, lineFormatter(key, res[k])); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// Sdump returns a string with the passed arguments formatted exactly the same as Dump.
-func (e *Encoder) Sdump(i interface{}) (string, error) {
-	m, err := e.ToStringMap(i)
-	if err != nil {
-		return "", err
+func (e *Encoder) fdumpInterface(w *result, i interface{}, roots []string) error {
+	if r, ok := e.typeRule(i); ok {
+		e.writeStub(w, r, roots, i)
+		return nil
 	}
-	res := ""
-	var keys []string
-	for k := range m {
-		keys = append(keys, k)
+	f := valueFromInterface(i)
+	// The metadata of the top level struct are written under its type name, as its fields are
+	nroots := roots
+	if len(roots) == 0 && !e.DisableTypePrefix && f.IsValid() && f.Kind() == reflect.Struct {
+		nroots = append(roots, f.Type().Name())
+	}
+	if e.ExtraFields.Address {
+		if addrs := pointerAddresses(i); len(addrs) > 0 {
+			e.writeMeta(w, nroots, e.metaKeys().Addr, strings.Join(addrs, " -> "))
+		}
+	}
+	if t := reflect.TypeOf(i); e.ExtraFields.DetailedType && t != nil {
+		// The node may already be typed when its value has been converted, as for []byte
+		if _, typed := w.data[e.metaKey(nroots, e.metaKeys().Type)]; !typed {
+			kind := t.Kind()
+			if f.IsValid() {
+				kind = f.Kind()
+			}
+			e.writeMeta(w, nroots, e.metaKeys().Type, typeString(t))
+			e.writeMeta(w, nroots, e.metaKeys().Kind, kind.String())
+		}
 	}
-	sort.Strings(keys)
-	for _, k := range keys {
-		res += fmt.Sprintf("%s: %s\n", k, m[k])
+	if ok, err := e.fdumpHTTP(w, i, roots); ok {
+		return err
 	}
-	return res, nil
-}
-
-func (e *Encoder) fdumpInterface(w map[string]interface{}, i interface{}, roots []string) error {
-	f := valueFromInterface(i)
 	k := reflect.ValueOf(i).Kind()
-	if k == reflect.Ptr && reflect.ValueOf(i).IsNil() || !validAndNotEmpty(f) {
+	isNil := k == reflect.Ptr && reflect.ValueOf(i).IsNil() || !f.IsValid()
+	if isNil || !validAndNotEmpty(f) {
 		if len(roots) == 0 {
 			return nil
 		}
+		if e.ExtraFields.Nil && isNil {
+			e.writeMeta(w, roots, e.metaKeys().Nil, true)
+		}
 		k := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
 		var prefix string
 		if e.Prefix != "" {
 			prefix = e.Prefix + e.Separator
 		}
-		w[prefix+k] = ""
+		w.data[prefix+k] = ""
+		e.recordMeta(w, roots, i)
 		return nil
 	}
+	if e.ExtraFields.Nil && (f.Kind() == reflect.Slice || f.Kind() == reflect.Map) && f.IsNil() {
+		e.writeMeta(w, roots, e.metaKeys().Nil, true)
+	}
 	switch f.Kind() {
 	case reflect.Struct:
-		if e.ExtraFields.Type {
-			nodeType := append(roots, "__Type__")
-			nodeTypeFormatted := strings.Join(sliceFormat(nodeType, e.Formatters), e.Separator)
-			w[nodeTypeFormatted] = f.Type().Name()
+		if e.ExtraFields.Type && !e.ExtraFields.DetailedType {
+			e.writeMeta(w, nroots, e.metaKeys().Type, f.Type().Name())
 		}
-		croots := roots
-		if len(roots) == 0 && !e.DisableTypePrefix {
-			croots = append(roots, f.Type().Name())
-		}
-		if err := e.fdumpStruct(w, f, croots); err != nil {
+		e.recordMeta(w, nroots, i)
+		if err := e.fdumpStruct(w, f, nroots); err != nil {
 			return err
 		}
 	case reflect.Array, reflect.Slice:
+		e.recordMeta(w, roots, i)
 		if err := e.fDumpArray(w, i, roots); err != nil {
 			return err
 		}
 		return nil
 	case reflect.Map:
-		if e.ExtraFields.Type {
-			nodeType := append(roots, "__Type__")
-			nodeTypeFormatted := strings.Join(sliceFormat(nodeType, e.Formatters), e.Separator)
-			w[nodeTypeFormatted] = "Map"
+		if e.ExtraFields.Type && !e.ExtraFields.DetailedType {
+			e.writeMeta(w, roots, e.metaKeys().Type, "Map")
 		}
+		e.recordMeta(w, roots, i)
 		if err := e.fDumpMap(w, i, roots); err != nil {
 			return err
 		}
 		return nil
 	default:
+		e.recordMeta(w, roots, i)
 		k := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
-		if e.ExtraFields.DeepJSON && (f.Kind() == reflect.String) {
+		if _, ok := f.Interface().(json.Number); ok {
+			var prefix string
+			if e.Prefix != "" {
+				prefix = e.Prefix + e.Separator
+			}
+			w.data[prefix+k] = f.Interface()
+		} else if e.ExtraFields.DeepJSON && (f.Kind() == reflect.String) {
 			if err := e.fDumpJSON(w, f.Interface().(string), roots, k); err != nil {
 				return err
 			}
+		} else if len(e.ExtraFields.DeepDecoders) > 0 && f.Kind() == reflect.String {
+			if err := e.fDumpDeep(w, f.String(), roots, k); err != nil {
+				return err
+			}
 		} else {
 			var prefix string
 			if e.Prefix != "" {
 				prefix = e.Prefix + e.Separator
 			}
-			w[prefix+k] = f.Interface()
+			w.data[prefix+k] = f.Interface()
 		}
 
 	}
 	return nil
 }
 
-func (e *Encoder) fDumpJSON(w map[string]interface{}, i string, roots []string, k string) error {
+// writeStub writes the placeholder of the rule r, if it is a stub, as the value of the node
+func (e *Encoder) writeStub(w *result, r typeRule, roots []string, i interface{}) {
+	if r.stub && len(roots) > 0 {
+		w.data[e.key(roots)] = r.placeholder
+		e.recordMeta(w, roots, i)
+	}
+}
+
+// key returns the key of the node, as data keys are written, without formatting roots in place
+func (e *Encoder) key(roots []string) string {
+	if len(roots) == 0 {
+		return e.Prefix
+	}
+	k := strings.Join(sliceFormat(append([]string(nil), roots...), e.Formatters), e.Separator)
+	if e.Prefix != "" {
+		return e.Prefix + e.Separator + k
+	}
+	return k
+}
+
+// recordMeta records the metadata of the node when they are requested. The first record of a node wins,
+// so that a value converted before being dumped, as []byte, keeps its original type.
+func (e *Encoder) recordMeta(w *result, roots []string, i interface{}) {
+	if w.meta == nil {
+		return
+	}
+	k := e.key(roots)
+	if _, ok := w.meta[k]; ok {
+		return
+	}
+	m := Meta{Secret: w.secret > 0}
+	t := reflect.TypeOf(i)
+	if t == nil {
+		m.Nil = true
+		w.meta[k] = m
+		return
+	}
+	f := valueFromInterface(i)
+	m.Type = typeString(t)
+	m.Kind = t.Kind()
+	if f.IsValid() {
+		m.Kind = f.Kind()
+	}
+	v := reflect.ValueOf(i)
+	m.Nil = v.Kind() == reflect.Ptr && v.IsNil() || !f.IsValid() ||
+		(f.Kind() == reflect.Slice || f.Kind() == reflect.Map) && f.IsNil()
+	m.Addr = strings.Join(pointerAddresses(i), " -> ")
+	w.meta[k] = m
+}
+
+// updateMeta updates the metadata of the node already recorded
+func (e *Encoder) updateMeta(w *result, roots []string, update func(m *Meta)) {
+	if w.meta == nil {
+		return
+	}
+	k := e.key(roots)
+	if m, ok := w.meta[k]; ok {
+		update(&m)
+		w.meta[k] = m
+	}
+}
+
+// metaKey returns the key of the metadata name of the node
+func (e *Encoder) metaKey(roots []string, name string) string {
+	return e.key(append(roots[:len(roots):len(roots)], name))
+}
+
+// writeMeta writes the metadata value of the node under the key made of roots and name
+func (e *Encoder) writeMeta(w *result, roots []string, name string, value interface{}) {
+	if w.meta != nil {
+		return
+	}
+	w.data[e.metaKey(roots, name)] = value
+}
+
+func (e *Encoder) fDumpJSON(w *result, i string, roots []string, k string) error {
 	var value interface{}
 	bodyJSONArray := []interface{}{}
 	// Try to parse as a json array
-	if err := json.Unmarshal([]byte(i), &bodyJSONArray); err != nil {
+	if err := decodeJSON(i, &bodyJSONArray); err != nil {
 		//Try to parse as a map
 		bodyJSONMap := map[string]interface{}{}
-		if err2 := json.Unmarshal([]byte(i), &bodyJSONMap); err2 == nil {
+		if err2 := decodeJSON(i, &bodyJSONMap); err2 == nil {
 			value = bodyJSONMap
 		} else {
 			value = i
@@ -170,12 +441,7 @@ func (e *Encoder) fDumpJSON(w map[string]interface{}, i string, roots []string,
 	}
 
 	if value == i {
-		var prefix string
-		if e.Prefix != "" {
-			prefix = e.Prefix + e.Separator
-		}
-		w[prefix+k] = i
-		return nil
+		return e.fDumpDeep(w, i, roots, k)
 	}
 	if err := e.fdumpInterface(w, value, roots); err != nil {
 		return err
@@ -183,8 +449,40 @@ func (e *Encoder) fDumpJSON(w map[string]interface{}, i string, roots []string,
 	return nil
 }
 
-func (e *Encoder) fDumpArray(w map[string]interface{}, i interface{}, roots []string) error {
+// fDumpRawJSON dumps the decoded raw JSON message in DeepJSON mode, or the message as a string
+func (e *Encoder) fDumpRawJSON(w *result, raw json.RawMessage, roots []string) error {
+	if e.ExtraFields.DeepJSON {
+		var value interface{}
+		if err := decodeJSON(string(raw), &value); err == nil {
+			return e.fdumpInterface(w, value, roots)
+		}
+	}
+	return e.fdumpInterface(w, string(raw), roots)
+}
+
+// fDumpDeep dumps the value decoded from i by the first of the DeepDecoders matching the key, or i itself
+func (e *Encoder) fDumpDeep(w *result, i string, roots []string, k string) error {
+	var prefix string
+	if e.Prefix != "" {
+		prefix = e.Prefix + e.Separator
+	}
+	for _, d := range e.ExtraFields.DeepDecoders {
+		if d.Decode == nil || !d.match(prefix+k) {
+			continue
+		}
+		if value, ok := d.Decode(i); ok {
+			return e.fdumpInterface(w, value, roots)
+		}
+	}
+	w.data[prefix+k] = i
+	return nil
+}
+
+func (e *Encoder) fDumpArray(w *result, i interface{}, roots []string) error {
 	f := valueFromInterface(i)
+	if raw, ok := f.Interface().(json.RawMessage); ok {
+		return e.fDumpRawJSON(w, raw, roots)
+	}
 	if _, ok := f.Interface().([]byte); ok {
 		if err := e.fdumpInterface(w, string(f.Interface().([]byte)), roots); err != nil {
 			return err
@@ -192,10 +490,8 @@ func (e *Encoder) fDumpArray(w map[string]interface{}, i interface{}, roots []st
 		return nil
 	}
 
-	if e.ExtraFields.Type {
-		nodeType := append(roots, "__Type__")
-		nodeTypeFormatted := strings.Join(sliceFormat(nodeType, e.Formatters), e.Separator)
-		w[nodeTypeFormatted] = "Array"
+	if e.ExtraFields.Type && !e.ExtraFields.DetailedType {
+		e.writeMeta(w, roots, e.metaKeys().Type, "Array")
 	}
 
 	v := reflect.ValueOf(i)
@@ -204,16 +500,11 @@ func (e *Encoder) fDumpArray(w map[string]interface{}, i interface{}, roots []st
 	}
 
 	if e.ExtraFields.Len {
-		nodeLen := append(roots, "__Len__")
-		nodeLenFormatted := strings.Join(sliceFormat(nodeLen, e.Formatters), e.Separator)
-		w[nodeLenFormatted] = v.Len()
-	}
-
-	if e.ExtraFields.DetailedArray && len(roots) > 0 {
-		structKey := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
-		w[structKey] = i
+		e.writeMeta(w, roots, e.metaKeys().Len, v.Len())
 	}
+	e.updateMeta(w, roots, func(m *Meta) { m.Len = v.Len() })
 
+	redacted := w.redacted
 	for i := 0; i < v.Len(); i++ {
 		var l string
 		var croots []string
@@ -235,14 +526,15 @@ func (e *Encoder) fDumpArray(w map[string]interface{}, i interface{}, roots []st
 		}
 		f := v.Index(i)
 
+		_, ignored := e.typeRule(f.Interface())
 		stringer, ok := f.Interface().(fmt.Stringer)
-		if ok {
+		if ok && !ignored {
 			k := strings.Join(sliceFormat(croots, e.Formatters), e.Separator)
 			var prefix string
 			if e.Prefix != "" {
 				prefix = e.Prefix
 			}
-			w[prefix+k] = stringer.String()
+			w.data[prefix+k] = stringer.String()
 		}
 
 		if err := e.fdumpInterface(w, f.Interface(), croots); err != nil {
@@ -250,13 +542,18 @@ func (e *Encoder) fDumpArray(w map[string]interface{}, i interface{}, roots []st
 		}
 	}
 
+	if e.ExtraFields.DetailedArray && len(roots) > 0 {
+		structKey := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
+		e.writeDetailed(w, structKey, i, redacted)
+	}
 	return nil
 }
 
-func (e *Encoder) fDumpMap(w map[string]interface{}, i interface{}, roots []string) error {
+func (e *Encoder) fDumpMap(w *result, i interface{}, roots []string) error {
 	v := reflect.ValueOf(i)
 
 	keys := v.MapKeys()
+	redacted := w.redacted
 	var lenKeys int64
 	for _, k := range keys {
 		key := fmt.Sprintf("%v", k.Interface())
@@ -269,11 +566,12 @@ func (e *Encoder) fDumpMap(w map[string]interface{}, i interface{}, roots []stri
 
 		f := valueFromInterface(value.Interface())
 
-		if validAndNotEmpty(f) && f.Type().Kind() == reflect.Struct {
+		_, ignored := e.typeRule(value.Interface())
+		if !ignored && validAndNotEmpty(f) && f.Type().Kind() == reflect.Struct {
 			stringer, ok := value.Interface().(fmt.Stringer)
 			if ok {
 				structKey := strings.Join(sliceFormat(croots, e.Formatters), e.Separator)
-				w[structKey] = stringer.String()
+				w.data[structKey] = stringer.String()
 			}
 			if !e.DisableTypePrefix {
 				croots = append(croots, f.Type().Name())
@@ -286,33 +584,27 @@ func (e *Encoder) fDumpMap(w map[string]interface{}, i interface{}, roots []stri
 	}
 
 	if e.ExtraFields.Len {
-		nodeLen := append(roots, "__Len__")
-		nodeLenFormatted := strings.Join(sliceFormat(nodeLen, e.Formatters), e.Separator)
-		w[nodeLenFormatted] = lenKeys
+		e.writeMeta(w, roots, e.metaKeys().Len, lenKeys)
 	}
+	e.updateMeta(w, roots, func(m *Meta) { m.Len = int(lenKeys) })
 	if e.ExtraFields.DetailedMap {
 		if len(roots) != 0 {
 			structKey := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
-			w[structKey] = i
+			e.writeDetailed(w, structKey, i, redacted)
 		}
 	}
 	return nil
 }
 
-func (e *Encoder) fdumpStruct(w map[string]interface{}, s reflect.Value, roots []string) error {
+func (e *Encoder) fdumpStruct(w *result, s reflect.Value, roots []string) error {
+	e.updateMeta(w, roots, func(m *Meta) { m.Len = s.NumField() })
 	if e.ExtraFields.DetailedStruct {
 		if e.ExtraFields.Len {
-			nodeLen := append(roots, "__Len__")
-			nodeLenFormatted := strings.Join(sliceFormat(nodeLen, e.Formatters), e.Separator)
-			w[nodeLenFormatted] = s.NumField()
-		}
-
-		structKey := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
-		if s.CanInterface() && len(roots) > 1 {
-			w[structKey] = s.Interface()
+			e.writeMeta(w, roots, e.metaKeys().Len, s.NumField())
 		}
 	}
 
+	redacted := w.redacted
 	var atLeastOneField bool
 	for i := 0; i < s.NumField(); i++ {
 		k := reflect.ValueOf(i).Kind()
@@ -321,7 +613,7 @@ func (e *Encoder) fdumpStruct(w map[string]interface{}, s reflect.Value, roots [
 				continue
 			}
 			k := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
-			w[k] = ""
+			w.data[k] = ""
 			atLeastOneField = true
 			continue
 		}
@@ -342,22 +634,57 @@ func (e *Encoder) fdumpStruct(w map[string]interface{}, s reflect.Value, roots [
 			croots = append(roots, s.Type().Field(i).Name)
 		}
 		atLeastOneField = true
-		if err := e.fdumpInterface(w, s.Field(i).Interface(), croots); err != nil {
+		secret := isSecretField(s.Type().Field(i))
+		if secret {
+			w.secret++
+		}
+		// The declared type of the field is checked as well, as nil interfaces have no type
+		if r, ok := e.typeRuleOf(s.Type().Field(i).Type); ok {
+			e.writeStub(w, r, croots, s.Field(i).Interface())
+		} else if secret && e.Redaction != "" {
+			e.writeRedaction(w, croots, s.Field(i).Interface())
+		} else if err := e.fdumpInterface(w, s.Field(i).Interface(), croots); err != nil {
 			return err
 		}
+		if secret {
+			w.secret--
+		}
+		name := s.Type().Field(i).Name
+		e.updateMeta(w, croots, func(m *Meta) { m.Field = name })
+	}
+
+	if e.ExtraFields.DetailedStruct && s.CanInterface() && len(roots) > 1 {
+		structKey := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
+		e.writeDetailed(w, structKey, s.Interface(), redacted)
 	}
 
 	if !atLeastOneField {
 		stringer, ok := s.Interface().(fmt.Stringer)
 		if ok {
 			structKey := strings.Join(sliceFormat(roots, e.Formatters), e.Separator)
-			w[structKey] = stringer.String()
+			w.data[structKey] = stringer.String()
 		}
 	}
 
 	return nil
 }
 
+// writeRedaction writes the Redaction in place of the secret value i
+func (e *Encoder) writeRedaction(w *result, roots []string, i interface{}) {
+	w.data[e.key(roots)] = e.Redaction
+	w.redacted++
+	e.recordMeta(w, roots, i)
+}
+
+// writeDetailed writes the whole value of a struct, map or array at key, or the Redaction if one of the fields
+// it holds has been redacted since the count was taken
+func (e *Encoder) writeDetailed(w *result, key string, i interface{}, redacted int) {
+	if w.redacted > redacted {
+		i = e.Redaction
+	}
+	w.data[key] = i
+}
+
 // ToStringMap formats the argument as a map[string]string. It formats exactly the same as Dump.
 func (e *Encoder) ToStringMap(i interface{}) (res map[string]string, err error) {
 	defer func() {
@@ -371,7 +698,10 @@ func (e *Encoder) ToStringMap(i interface{}) (res map[string]string, err error)
 		}
 	}()
 	ires := map[string]interface{}{}
-	if err = e.fdumpInterface(ires, i, nil); err != nil {
+	if err = e.fdumpInterface(&result{data: ires}, i, nil); err != nil {
+		return
+	}
+	if err = e.filter(ires, nil); err != nil {
 		return
 	}
 	res = map[string]string{}
@@ -394,12 +724,109 @@ func (e *Encoder) ToMap(i interface{}) (res map[string]interface{}, err error) {
 		}
 	}()
 	res = map[string]interface{}{}
-	if err = e.fdumpInterface(res, i, nil); err != nil {
+	if err = e.fdumpInterface(&result{data: res}, i, nil); err != nil {
+		return
+	}
+	err = e.filter(res, nil)
+	return
+}
+
+// ToMapWithMeta dumps argument as a map[string]interface{} and returns the metadata of the keys separately.
+// Metadata are collected for every key, including the keys of structs, maps and arrays which have no value
+// in data, whatever the ExtraFields options are, and are never written to data.
+func (e *Encoder) ToMapWithMeta(i interface{}) (data map[string]interface{}, meta map[string]Meta, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(runtime.Error); ok {
+				panic(r)
+			}
+			err = r.(error)
+			buf := make([]byte, 1<<16)
+			runtime.Stack(buf, true)
+		}
+	}()
+	data = map[string]interface{}{}
+	meta = map[string]Meta{}
+	if err = e.fdumpInterface(&result{data: data, meta: meta}, i, nil); err != nil {
 		return
 	}
+	err = e.filter(data, meta)
 	return
 }
 
+// filter removes the keys which do not match Filters from data, and from meta unless they are parents of
+// the remaining keys
+func (e *Encoder) filter(data map[string]interface{}, meta map[string]Meta) error {
+	if len(e.Filters) == 0 {
+		return nil
+	}
+	for _, p := range e.Filters {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid filter %q: %v", p, err)
+		}
+	}
+	for k := range data {
+		if !e.matchFilters(k) {
+			delete(data, k)
+		}
+	}
+	if meta == nil {
+		return nil
+	}
+	parents := map[string]bool{}
+	for k := range data {
+		// The root key is the parent of every other key
+		parents[""] = parents[""] || k != ""
+		for _, p := range e.parentKeys(k) {
+			parents[p] = true
+		}
+	}
+	for k := range meta {
+		if !parents[k] && !e.matchFilters(k) {
+			delete(meta, k)
+		}
+	}
+	return nil
+}
+
+// matchFilters returns true if k, or one of its parents, matches one of the Filters
+func (e *Encoder) matchFilters(k string) bool {
+	for _, p := range e.Filters {
+		for n := len(k); n > 0; n-- {
+			if n < len(k) && !e.isParentKey(k[:n], k) {
+				continue
+			}
+			if ok, _ := path.Match(p, k[:n]); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parentKeys returns the keys k is nested under, from the closest one
+func (e *Encoder) parentKeys(k string) []string {
+	var parents []string
+	for n := len(k) - 1; n > 0; n-- {
+		if e.isParentKey(k[:n], k) {
+			parents = append(parents, k[:n])
+		}
+	}
+	return parents
+}
+
+// isParentKey returns true if the key k is nested under the key p
+func (e *Encoder) isParentKey(p, k string) bool {
+	if p == "" {
+		return k != ""
+	}
+	if len(k) <= len(p) || !strings.HasPrefix(k, p) {
+		return false
+	}
+	rest := k[len(p):]
+	return e.Separator != "" && strings.HasPrefix(rest, e.Separator) || e.ArrayJSONNotation && rest[0] == '['
+}
+
 func (e *Encoder) ViperKey(s string) string {
 	if e.Prefix != "" {
 		s = strings.Replace(s, e.Prefix+e.Separator, "", 1)
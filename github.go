@@ -0,0 +1,59 @@
+package dump
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteGitHubOutput writes the dumped keys and values of i to w in the format of the $GITHUB_OUTPUT and $GITHUB_ENV
+// files of GitHub Actions. Single line values are written as key=value, multi-line values with the key<<DELIMITER
+// syntax and a random delimiter which does not appear in the value.
+func (e *Encoder) WriteGitHubOutput(w io.Writer, i interface{}) error {
+	res, err := e.ToStringMap(i)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(res))
+	for k := range res {
+		if strings.ContainsAny(k, "=\r\n") || strings.Contains(k, "<<") {
+			return fmt.Errorf("invalid GitHub output name %q", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := res[k]
+		if !strings.ContainsAny(v, "\r\n") {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", k, v); err != nil {
+				return err
+			}
+			continue
+		}
+		delimiter, err := githubDelimiter(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s<<%s\n%s\n%s\n", k, delimiter, v, delimiter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubDelimiter returns a random heredoc delimiter which does not appear in v
+func githubDelimiter(v string) (string, error) {
+	b := make([]byte, 16)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		delimiter := "ghadelimiter_" + hex.EncodeToString(b)
+		if !strings.Contains(v, delimiter) {
+			return delimiter, nil
+		}
+	}
+}
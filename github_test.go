@@ -0,0 +1,37 @@
+package dump_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestWriteGitHubOutput(t *testing.T) {
+	type Build struct {
+		Version string
+		Notes   string
+		Tags    []string
+	}
+
+	b := Build{Version: "1.2.3", Notes: "first line\nsecond line", Tags: []string{"latest"}}
+
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.Separator = "_"
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	err := e.WriteGitHubOutput(out, b)
+	require.NoError(t, err)
+
+	matches := regexp.MustCompile(`^notes<<(ghadelimiter_[0-9a-f]{32})\nfirst line\nsecond line\n(ghadelimiter_[0-9a-f]{32})\ntags_tags0=latest\nversion=1.2.3\n$`).FindStringSubmatch(out.String())
+	require.Len(t, matches, 3, out.String())
+	assert.Equal(t, matches[1], matches[2])
+
+	err = e.WriteGitHubOutput(out, map[string]string{"a=b": "c"})
+	assert.Error(t, err)
+}
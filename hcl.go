@@ -0,0 +1,111 @@
+package dump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hclIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// WriteHCL writes i to w as a Terraform .tfvars file: the top level keys are written as variables, structs and maps
+// as objects and arrays as lists, nested the same way as the dumped keys. Keys are named the way go-dump names
+// them, with the formatters and struct tags options of the encoder, and values keep their types.
+// The type prefix and the Prefix are not written, so that the fields of a struct are the variables.
+func (e *Encoder) WriteHCL(w io.Writer, i interface{}) error {
+	enc := *e
+	enc.DisableTypePrefix = true
+	enc.Prefix = ""
+	root, err := enc.document(i, "HCL")
+	if err != nil {
+		return err
+	}
+	if root.isList() {
+		return fmt.Errorf("unable to write %T as HCL: the top level is not an object", i)
+	}
+	for _, n := range root.children {
+		if !hclIdentifierRegexp.MatchString(n.label) {
+			return fmt.Errorf("invalid HCL variable name %q", n.label)
+		}
+		if _, err := fmt.Fprintf(w, "%s = %s\n", n.label, hclValue(n, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hclValue returns the node as an HCL expression, objects and lists of objects are written on several lines
+func hclValue(n *node, indent int) string {
+	if !n.isContainer() {
+		return hclScalar(n.scalar())
+	}
+	prefix := strings.Repeat("  ", indent+1)
+	end := strings.Repeat("  ", indent)
+	if n.isList() {
+		if len(n.children) == 0 {
+			return "[]"
+		}
+		var inline bool
+		values := make([]string, len(n.children))
+		for i, c := range n.children {
+			values[i] = hclValue(c, indent+1)
+			inline = inline || !c.isContainer()
+		}
+		if inline {
+			return "[" + strings.Join(values, ", ") + "]"
+		}
+		return "[\n" + prefix + strings.Join(values, ",\n"+prefix) + ",\n" + end + "]"
+	}
+	if len(n.children) == 0 {
+		return "{}"
+	}
+	lines := make([]string, len(n.children))
+	for i, c := range n.children {
+		key := c.label
+		if !hclIdentifierRegexp.MatchString(key) {
+			key = hclString(key)
+		}
+		lines[i] = prefix + key + " = " + hclValue(c, indent+1)
+	}
+	return "{\n" + strings.Join(lines, "\n") + "\n" + end + "}"
+}
+
+func hclScalar(v interface{}) string {
+	switch v := v.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return hclString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case json.Number:
+		return v.String()
+	case string:
+		return hclString(v)
+	}
+	return "null"
+}
+
+var hclReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r", "\t", "\\t", "${", "$${", "%{", "%%{")
+
+// hclString returns s as a quoted HCL string, with template sequences escaped
+func hclString(s string) string {
+	var b strings.Builder
+	for _, r := range hclReplacer.Replace(s) {
+		if r < 0x20 || r == 0x7f {
+			fmt.Fprintf(&b, `\u%04X`, r)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return "\"" + b.String() + "\""
+}
@@ -0,0 +1,61 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestWriteHCL(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.Prefix = "APP"
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	err := e.WriteHCL(out, testNestedConfig)
+	assert.NoError(t, err)
+
+	expected := `backup = null
+debug = true
+empty = []
+labels = {
+  env = "prod"
+  "team.name" = "core"
+}
+name = "my app: prod"
+pool = {
+  max = 20
+  timeout = 1.5
+}
+ports = [80, 443]
+servers = [
+  {
+    host = "a.local"
+    port = 8080
+  },
+  {
+    host = "b.local"
+    port = 8081
+  },
+]
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteHCL(out, map[string]string{"template": "${var.name}"})
+	assert.NoError(t, err)
+	assert.Equal(t, "template = \"$${var.name}\"\n", out.String())
+
+	err = e.WriteHCL(out, map[string]string{"1st": "a"})
+	assert.Error(t, err)
+
+	out.Reset()
+	err = dump.NewDefaultEncoder().WriteHCL(out, struct {
+		Region string
+		Zones  []string
+	}{Region: "eu-west-1", Zones: []string{"a", "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Region = \"eu-west-1\"\nZones = [\"a\", \"b\"]\n", out.String())
+}
@@ -1,7 +1,12 @@
 package dump
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -48,6 +53,26 @@ func NoFormatter() KeyFormatterFunc {
 	}
 }
 
+// LineFormatterFunc is a type for line formatting, the key is padded when keys are aligned
+type LineFormatterFunc func(key, value string) string
+
+// WithDefaultLineFormatter formats lines as "key: value", or "key:" when the value is empty
+func WithDefaultLineFormatter() LineFormatterFunc {
+	return func(key, value string) string {
+		if value == "" {
+			return key + ":\n"
+		}
+		return key + ": " + value + "\n"
+	}
+}
+
+// WithLineFormat formats lines with a fmt format taking the key and the value, such as "%s = %s\n" or "%s\t%s\n"
+func WithLineFormat(format string) LineFormatterFunc {
+	return func(key, value string) string {
+		return fmt.Sprintf(format, key, value)
+	}
+}
+
 func valueFromInterface(i interface{}) reflect.Value {
 	var f reflect.Value
 	if reflect.ValueOf(i).Kind() == reflect.Ptr {
@@ -66,6 +91,58 @@ func valueFromInterface(i interface{}) reflect.Value {
 	return f
 }
 
+// pointerAddresses returns the addresses of all the pointers followed to reach the final value of i
+func pointerAddresses(i interface{}) []string {
+	var addrs []string
+	v := reflect.ValueOf(i)
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		if v.Kind() == reflect.Ptr {
+			addrs = append(addrs, fmt.Sprintf("0x%x", v.Pointer()))
+		}
+		v = v.Elem()
+	}
+	return addrs
+}
+
+// typeString returns the fully qualified name of the type t, such as *github.com/acme/cfg.Database
+func typeString(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() != "" {
+			return t.PkgPath() + "." + t.Name()
+		}
+		return t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeString(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeString(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeString(t.Elem()))
+	case reflect.Map:
+		return "map[" + typeString(t.Key()) + "]" + typeString(t.Elem())
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + typeString(t.Elem())
+		case reflect.SendDir:
+			return "chan<- " + typeString(t.Elem())
+		}
+		return "chan " + typeString(t.Elem())
+	}
+	return t.String()
+}
+
+// isSecretField returns true if the field is tagged `dump:"secret"`
+func isSecretField(f reflect.StructField) bool {
+	for _, opt := range strings.Split(f.Tag.Get("dump"), ",") {
+		if opt == "secret" {
+			return true
+		}
+	}
+	return false
+}
+
 func validAndNotEmpty(v reflect.Value) bool {
 	if v.IsValid() && v.CanInterface() {
 		if v.Kind() == reflect.String {
@@ -89,3 +166,24 @@ func format(s string, formatters []KeyFormatterFunc, level int) string {
 	}
 	return s
 }
+
+// decodeJSON decodes the JSON document s into v, with the numbers as json.Number to keep their precision
+func decodeJSON(s string, v interface{}) error {
+	d := json.NewDecoder(strings.NewReader(s))
+	d.UseNumber()
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// Float32Value returns f as the float64 of its shortest decimal representation. Converting a float32 to a float64
+// keeps its binary value, so that float32(0.1) would be written as 0.10000000149011612 rather than 0.1.
+// It is used wherever go-dump writes float32 values as numbers, and by the packages built on go-dump.
+func Float32Value(f float32) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return v
+}
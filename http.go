@@ -0,0 +1,174 @@
+package dump
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// secretHeaders are the headers holding credentials, they are dumped as secrets
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// fdumpHTTP dumps the requests, the responses and the headers of net/http by their meaningful parts rather than
+// by their fields. It returns false if i is none of them.
+func (e *Encoder) fdumpHTTP(w *result, i interface{}, roots []string) (bool, error) {
+	switch v := i.(type) {
+	case *http.Request:
+		if v == nil {
+			return false, nil
+		}
+		roots = e.httpRoots(w, roots, "Request", i)
+		var u string
+		if v.URL != nil {
+			u = e.redactURL(v.URL)
+		}
+		for _, f := range []struct{ name, value string }{
+			{"Method", v.Method},
+			{"URL", u},
+			{"Proto", v.Proto},
+			{"Host", v.Host},
+		} {
+			if err := e.fdumpInterface(w, f.value, e.childRoots(roots, f.name)); err != nil {
+				return true, err
+			}
+		}
+		if err := e.fdumpHeader(w, v.Header, e.childRoots(roots, "Header")); err != nil {
+			return true, err
+		}
+		body, err := e.fdumpBody(w, v.Body, v.Header, e.childRoots(roots, "Body"))
+		v.Body = body
+		return true, err
+	case *http.Response:
+		if v == nil {
+			return false, nil
+		}
+		roots = e.httpRoots(w, roots, "Response", i)
+		for _, f := range []struct {
+			name  string
+			value interface{}
+		}{
+			{"Status", v.Status},
+			{"StatusCode", v.StatusCode},
+			{"Proto", v.Proto},
+		} {
+			if err := e.fdumpInterface(w, f.value, e.childRoots(roots, f.name)); err != nil {
+				return true, err
+			}
+		}
+		if err := e.fdumpHeader(w, v.Header, e.childRoots(roots, "Header")); err != nil {
+			return true, err
+		}
+		body, err := e.fdumpBody(w, v.Body, v.Header, e.childRoots(roots, "Body"))
+		v.Body = body
+		return true, err
+	case http.Header:
+		return true, e.fdumpHeader(w, v, roots)
+	}
+	return false, nil
+}
+
+// httpRoots records the metadata of the request or the response i and returns its roots, prefixed by its type
+// name at top level
+func (e *Encoder) httpRoots(w *result, roots []string, name string, i interface{}) []string {
+	if len(roots) == 0 && !e.DisableTypePrefix {
+		roots = []string{name}
+	}
+	if e.ExtraFields.Type && !e.ExtraFields.DetailedType {
+		e.writeMeta(w, roots, e.metaKeys().Type, name)
+	}
+	e.recordMeta(w, roots, i)
+	return roots
+}
+
+// childRoots returns the roots of the child name, without sharing the backing array of roots
+func (e *Encoder) childRoots(roots []string, name string) []string {
+	return append(roots[:len(roots):len(roots)], name)
+}
+
+// fdumpHeader dumps a key per header, with a value or, for headers with several values, indexed values.
+// The headers holding credentials are secrets.
+func (e *Encoder) fdumpHeader(w *result, h http.Header, roots []string) error {
+	e.recordMeta(w, roots, h)
+	e.updateMeta(w, roots, func(m *Meta) { m.Len = len(h) })
+	for name, values := range h {
+		var value interface{} = values
+		if len(values) == 1 {
+			value = values[0]
+		}
+		secret := secretHeaders[http.CanonicalHeaderKey(name)]
+		if secret {
+			w.secret++
+		}
+		var err error
+		if secret && e.Redaction != "" {
+			e.writeRedaction(w, e.childRoots(roots, name), value)
+		} else {
+			err = e.fdumpInterface(w, value, e.childRoots(roots, name))
+		}
+		if secret {
+			w.secret--
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redactURL returns u as a string, with the password of its user info replaced by the Redaction if it is set
+func (e *Encoder) redactURL(u *url.URL) string {
+	if e.Redaction == "" || u.User == nil {
+		return u.String()
+	}
+	if _, ok := u.User.Password(); !ok {
+		return u.String()
+	}
+	// The Redaction is not escaped as the password would be
+	redacted := *u
+	redacted.User = url.UserPassword(u.User.Username(), "x")
+	return strings.Replace(redacted.String(), ":x@", ":"+e.Redaction+"@", 1)
+}
+
+// httpBodyLimit is the number of bytes of the bodies which are read and dumped, longer bodies are dumped
+// truncated, as strings, and the rest of their stream is left unread
+const httpBodyLimit = 64 << 10
+
+// fdumpBody reads and dumps the body, parsed as in DeepJSON mode if the content type is JSON,
+// and returns a body to read it again. At most httpBodyLimit bytes are read.
+func (e *Encoder) fdumpBody(w *result, body io.ReadCloser, h http.Header, roots []string) (io.ReadCloser, error) {
+	if body == nil || body == http.NoBody {
+		return body, nil
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(body, httpBodyLimit+1))
+	if err != nil {
+		return readCloser{io.MultiReader(bytes.NewReader(b), body), body}, err
+	}
+	if len(b) > httpBodyLimit {
+		err = e.fdumpInterface(w, string(b[:httpBodyLimit])+"...(truncated)", roots)
+		return readCloser{io.MultiReader(bytes.NewReader(b), body), body}, err
+	}
+	body.Close()
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		k := strings.Join(sliceFormat(append([]string(nil), roots...), e.Formatters), e.Separator)
+		err = e.fDumpJSON(w, string(b), roots, k)
+	} else {
+		err = e.fdumpInterface(w, string(b), roots)
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), err
+}
+
+// readCloser reads the bytes already read from a body, then the rest of it, and closes the body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
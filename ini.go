@@ -0,0 +1,84 @@
+package dump
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// INIOptions holds the options of WriteINI
+type INIOptions struct {
+	// SectionDepth is the number of segments of the keys written as section names
+	SectionDepth int
+	// QuoteValues quotes every value, rather than only the values which need to be
+	QuoteValues bool
+}
+
+// WriteINI writes the dumped keys and values of i to w as an INI file. The first SectionDepth segments of the
+// keys are written as [section] headers and the remaining segments as keys. Keys with no more segments than
+// the section depth are written before the first section. Values are quoted when they need to be, or always
+// if QuoteValues is set. Nil options stand for a section depth of 1.
+func (e *Encoder) WriteINI(w io.Writer, i interface{}, opts *INIOptions) error {
+	if opts == nil {
+		opts = &INIOptions{SectionDepth: 1}
+	}
+	res, err := e.ToStringMap(i)
+	if err != nil {
+		return err
+	}
+
+	sections := map[string]map[string]string{}
+	for k, v := range res {
+		var section string
+		if opts.SectionDepth > 0 && e.Separator != "" {
+			segments := strings.Split(k, e.Separator)
+			if len(segments) > opts.SectionDepth {
+				section = strings.Join(segments[:opts.SectionDepth], e.Separator)
+				k = strings.Join(segments[opts.SectionDepth:], e.Separator)
+			}
+		}
+		if sections[section] == nil {
+			sections[section] = map[string]string{}
+		}
+		sections[section][k] = v
+	}
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for n, name := range names {
+		if name != "" {
+			if n > 0 {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "[%s]\n", name); err != nil {
+				return err
+			}
+		}
+		keys := make([]string, 0, len(sections[name]))
+		for k := range sections[name] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if _, err := fmt.Fprintf(w, "%s = %s\n", k, iniValue(sections[name][k], opts.QuoteValues)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var iniReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r", "\t", "\\t")
+
+func iniValue(v string, quote bool) string {
+	if quote || strings.TrimSpace(v) != v || strings.ContainsAny(v, ";#\"\\\n\r\t") {
+		return "\"" + iniReplacer.Replace(v) + "\""
+	}
+	return v
+}
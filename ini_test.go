@@ -0,0 +1,57 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestWriteINI(t *testing.T) {
+	type Program struct {
+		Command   string
+		Autostart bool
+		Env       map[string]string
+	}
+	type Config struct {
+		Name     string
+		Programs map[string]Program
+	}
+
+	cfg := Config{
+		Name: "supervisor",
+		Programs: map[string]Program{
+			"web": {Command: "/bin/web --port=80", Autostart: true, Env: map[string]string{"GREETING": "hello; world"}},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	err := e.WriteINI(out, cfg, nil)
+	assert.NoError(t, err)
+
+	expected := `Name = supervisor
+
+[Programs]
+web.Autostart = true
+web.Command = /bin/web --port=80
+web.Env.GREETING = "hello; world"
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteINI(out, cfg, &dump.INIOptions{SectionDepth: 2, QuoteValues: true})
+	assert.NoError(t, err)
+
+	expected = `Name = "supervisor"
+
+[Programs.web]
+Autostart = "true"
+Command = "/bin/web --port=80"
+Env.GREETING = "hello; world"
+`
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,146 @@
+package dump
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	kubernetesKeyRegexp  = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	kubernetesEnvRegexp  = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+	kubernetesNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// kubernetesEntry is a dumped key and value written to a Kubernetes manifest
+type kubernetesEntry struct {
+	key    string
+	value  string
+	secret bool
+}
+
+// kubernetesEntries dumps i and returns its sorted keys and values, the keys are checked against pattern
+func (e *Encoder) kubernetesEntries(i interface{}, pattern *regexp.Regexp) ([]kubernetesEntry, error) {
+	data, meta, err := e.ToMapWithMeta(i)
+	if err != nil {
+		return nil, err
+	}
+	// The values of structs, maps and arrays, as written by the Detailed extra fields, hold their secret content
+	secretParents := map[string]bool{}
+	for k, m := range meta {
+		if m.Secret {
+			for _, p := range e.parentKeys(k) {
+				secretParents[p] = true
+			}
+		}
+	}
+	entries := make([]kubernetesEntry, 0, len(data))
+	for k, v := range data {
+		if len(k) > 253 || !pattern.MatchString(k) {
+			return nil, fmt.Errorf("invalid Kubernetes key %q: must be at most 253 characters matching %s", k, pattern)
+		}
+		// Keys without metadata are not known to be safe
+		m, ok := meta[k]
+		secret := !ok || m.Secret || secretParents[k]
+		entries = append(entries, kubernetesEntry{key: k, value: printValue(v), secret: secret})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	return entries, nil
+}
+
+func checkKubernetesName(name string) error {
+	if len(name) > 253 || !kubernetesNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid Kubernetes name %q: must be a lowercase RFC 1123 subdomain", name)
+	}
+	return nil
+}
+
+// WriteConfigMap writes to w a Kubernetes ConfigMap manifest named name, with the dumped keys and values of i
+// as data. The values of the fields tagged `dump:"secret"`, of the keys holding them and of the keys without
+// metadata are left out, see WriteSecret.
+func (e *Encoder) WriteConfigMap(w io.Writer, name string, i interface{}) error {
+	return e.writeKubernetesData(w, "ConfigMap", name, i, false)
+}
+
+// WriteSecret writes to w a Kubernetes Secret manifest named name, with the base64 encoded values of the fields
+// of i tagged `dump:"secret"` as data, and of the keys left out by WriteConfigMap.
+func (e *Encoder) WriteSecret(w io.Writer, name string, i interface{}) error {
+	return e.writeKubernetesData(w, "Secret", name, i, true)
+}
+
+func (e *Encoder) writeKubernetesData(w io.Writer, kind, name string, i interface{}, secret bool) error {
+	if err := checkKubernetesName(name); err != nil {
+		return err
+	}
+	entries, err := e.kubernetesEntries(i, kubernetesKeyRegexp)
+	if err != nil {
+		return err
+	}
+
+	lines := []string{
+		"apiVersion: v1",
+		"kind: " + kind,
+		"metadata:",
+		"  name: " + name,
+	}
+	if secret {
+		lines = append(lines, "type: Opaque")
+	}
+	var data []string
+	for _, entry := range entries {
+		if entry.secret != secret {
+			continue
+		}
+		value := entry.value
+		if secret {
+			value = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+		data = append(data, "  "+yamlString(entry.key)+": "+yamlString(value))
+	}
+	if len(data) == 0 {
+		lines = append(lines, "data: {}")
+	} else {
+		lines = append(lines, "data:")
+		lines = append(lines, data...)
+	}
+	_, err = io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// WriteEnvList writes the dumped keys and values of i to w as the env list of a Kubernetes container. The values of
+// the fields tagged `dump:"secret"` are read from the Secret named secretName, as written by WriteSecret, or are
+// written inline if secretName is empty.
+func (e *Encoder) WriteEnvList(w io.Writer, secretName string, i interface{}) error {
+	if secretName != "" {
+		if err := checkKubernetesName(secretName); err != nil {
+			return err
+		}
+	}
+	entries, err := e.kubernetesEntries(i, kubernetesEnvRegexp)
+	if err != nil {
+		return err
+	}
+
+	lines := []string{"env:"}
+	if len(entries) == 0 {
+		lines = []string{"env: []"}
+	}
+	for _, entry := range entries {
+		lines = append(lines, "  - name: "+yamlString(entry.key))
+		if entry.secret && secretName != "" {
+			lines = append(lines,
+				"    valueFrom:",
+				"      secretKeyRef:",
+				"        name: "+secretName,
+				"        key: "+yamlString(entry.key),
+			)
+			continue
+		}
+		lines = append(lines, "    value: "+yamlString(entry.value))
+	}
+	_, err = io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
@@ -0,0 +1,151 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+type kubernetesConfig struct {
+	Host     string
+	Port     int
+	Debug    bool
+	Password string `dump:"secret"`
+	Auth     struct {
+		Token string
+	} `dump:"secret"`
+}
+
+func newKubernetesEncoder() *dump.Encoder {
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.Separator = "_"
+	e.Prefix = "APP"
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultUpperCaseFormatter()}
+	return e
+}
+
+func TestWriteConfigMapAndSecret(t *testing.T) {
+	cfg := kubernetesConfig{Host: "db.local", Port: 5432, Debug: true, Password: "s3cr3t"}
+	cfg.Auth.Token = "token"
+
+	out := &bytes.Buffer{}
+	e := newKubernetesEncoder()
+	err := e.WriteConfigMap(out, "app-config", cfg)
+	assert.NoError(t, err)
+
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  APP_DEBUG: "true"
+  APP_HOST: db.local
+  APP_PORT: "5432"
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteSecret(out, "app-secret", cfg)
+	assert.NoError(t, err)
+
+	expected = `apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secret
+type: Opaque
+data:
+  APP_AUTH_TOKEN: dG9rZW4=
+  APP_PASSWORD: czNjcjN0
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteEnvList(out, "app-secret", cfg)
+	assert.NoError(t, err)
+
+	expected = `env:
+  - name: APP_AUTH_TOKEN
+    valueFrom:
+      secretKeyRef:
+        name: app-secret
+        key: APP_AUTH_TOKEN
+  - name: APP_DEBUG
+    value: "true"
+  - name: APP_HOST
+    value: db.local
+  - name: APP_PASSWORD
+    valueFrom:
+      secretKeyRef:
+        name: app-secret
+        key: APP_PASSWORD
+  - name: APP_PORT
+    value: "5432"
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteConfigMapDetailedSecrets(t *testing.T) {
+	type Database struct {
+		User     string
+		Password string `dump:"secret"`
+	}
+	type Outer struct {
+		Name string
+		DB   Database
+		DBs  map[string]Database
+	}
+	cfg := Outer{
+		Name: "app",
+		DB:   Database{User: "u", Password: "hunter2"},
+		DBs:  map[string]Database{"main": {User: "m", Password: "pw"}},
+	}
+
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.ExtraFields.DetailedStruct = true
+	e.ExtraFields.DetailedMap = true
+	err := e.WriteConfigMap(out, "app-config", cfg)
+	assert.NoError(t, err)
+
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  Outer.DB.User: u
+  Outer.DBs.main.Database.User: m
+  Outer.Name: app
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteSecret(out, "app-secret", cfg)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "  Outer.DB: ")
+	assert.Contains(t, out.String(), "  Outer.DBs: ")
+	assert.Contains(t, out.String(), "  Outer.DB.Password: aHVudGVyMg==\n")
+}
+
+func TestWriteConfigMapInvalidKeys(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := newKubernetesEncoder()
+	err := e.WriteConfigMap(out, "app-config", map[string]string{"with space": "value"})
+	assert.NoError(t, err, "spaces are replaced by the default formatter")
+
+	err = e.WriteConfigMap(out, "app-config", map[string]string{"a@b": "value"})
+	assert.Error(t, err)
+
+	err = e.WriteEnvList(out, "", map[string]string{"a": "value"})
+	assert.NoError(t, err)
+
+	e.Prefix = ""
+	err = e.WriteEnvList(out, "", map[string]string{"1A": "value"})
+	assert.Error(t, err)
+
+	err = e.WriteConfigMap(out, "App_Config", map[string]string{"a": "value"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+type nestedPool struct {
+	Max     int
+	Timeout float64
+}
+
+type nestedServer struct {
+	Host string
+	Port int
+}
+
+type nestedConfig struct {
+	Name    string `json:"name"`
+	Debug   bool
+	Ports   []int
+	Pool    *nestedPool
+	Backup  *nestedPool
+	Labels  map[string]string
+	Servers []nestedServer
+	Empty   []string
+}
+
+var testNestedConfig = nestedConfig{
+	Name:   "my app: prod",
+	Debug:  true,
+	Ports:  []int{80, 443},
+	Pool:   &nestedPool{Max: 20, Timeout: 1.5},
+	Labels: map[string]string{"env": "prod", "team.name": "core"},
+	Servers: []nestedServer{
+		{Host: "a.local", Port: 8080},
+		{Host: "b.local", Port: 8081},
+	},
+	Empty: []string{},
+}
+
+func TestWriteYAML(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.ExtraFields.UseJSONTag = true
+	err := e.WriteYAML(out, testNestedConfig)
+	assert.NoError(t, err)
+
+	expected := `Backup: null
+Debug: true
+Empty: []
+Labels:
+  env: prod
+  team.name: core
+Pool:
+  Max: 20
+  Timeout: 1.5
+Ports:
+  - 80
+  - 443
+Servers:
+  - Host: a.local
+    Port: 8080
+  - Host: b.local
+    Port: 8081
+name: "my app: prod"
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteYAML(out, [][]string{{"a", "true"}, {}})
+	assert.NoError(t, err)
+
+	expected = `- - a
+  - "true"
+- []
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteTOML(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.ExtraFields.UseJSONTag = true
+	err := e.WriteTOML(out, testNestedConfig)
+	assert.NoError(t, err)
+
+	expected := `Debug = true
+Empty = []
+Ports = [80, 443]
+name = "my app: prod"
+
+[Labels]
+env = "prod"
+"team.name" = "core"
+
+[Pool]
+Max = 20
+Timeout = 1.5
+
+[[Servers]]
+Host = "a.local"
+Port = 8080
+
+[[Servers]]
+Host = "b.local"
+Port = 8081
+`
+	assert.Equal(t, expected, out.String())
+
+	err = e.WriteTOML(out, []string{"a"})
+	assert.Error(t, err)
+}
+
+func TestWriteNestedValues(t *testing.T) {
+	e := dump.NewDefaultEncoder()
+
+	out := &bytes.Buffer{}
+	err := e.WriteYAML(out, map[string]float32{"ratio": 0.1})
+	assert.NoError(t, err)
+	assert.Equal(t, "ratio: 0.1\n", out.String())
+
+	out.Reset()
+	err = e.WriteTOML(out, map[string]float32{"ratio": 0.1})
+	assert.NoError(t, err)
+	assert.Equal(t, "ratio = 0.1\n", out.String())
+
+	conflict := map[string]string{"db": "x", "db.host": "y"}
+	assert.Error(t, e.WriteYAML(out, conflict))
+	assert.Error(t, e.WriteTOML(out, conflict))
+	assert.Error(t, e.WriteHCL(out, conflict))
+	assert.Error(t, e.WriteYAML(out, "hello"))
+	assert.Error(t, e.WriteTOML(out, 42))
+}
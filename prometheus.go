@@ -0,0 +1,153 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusOptions holds the options of WritePrometheus
+type PrometheusOptions struct {
+	// MetricName is the name of the metric, or the prefix of the metric names if MetricPerKey is set
+	MetricName string
+	// MetricPerKey writes a metric per key rather than a single metric with the key as label
+	MetricPerKey bool
+}
+
+// WritePrometheus writes the numeric and boolean values dumped from i to w in the Prometheus text exposition format,
+// as gauges. Values are written as a single metric named MetricName, go_dump by default, with the key as label,
+// or as a metric per key, named after the sanitized key prefixed by MetricName, if MetricPerKey is set.
+// Booleans are written as 1 or 0, other values are left out. opts may be nil.
+func (e *Encoder) WritePrometheus(w io.Writer, i interface{}, opts *PrometheusOptions) error {
+	if opts == nil {
+		opts = &PrometheusOptions{}
+	}
+	data, err := e.ToMap(i)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(data))
+	values := map[string]float64{}
+	for k, v := range data {
+		if f, ok := prometheusValue(v); ok {
+			keys = append(keys, k)
+			values[k] = f
+		}
+	}
+	sort.Strings(keys)
+
+	if !opts.MetricPerKey {
+		if len(keys) == 0 {
+			return nil
+		}
+		name := "go_dump"
+		if opts.MetricName != "" {
+			name = prometheusName(opts.MetricName)
+		}
+		if _, err := fmt.Fprintf(w, "# TYPE %s gauge\n", name); err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if _, err := fmt.Fprintf(w, "%s{key=\"%s\"} %s\n", name, prometheusLabelReplacer.Replace(k), prometheusFloat(values[k])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	names := map[string]string{}
+	for _, k := range keys {
+		name := k
+		if opts.MetricName != "" {
+			name = opts.MetricName + "_" + k
+		}
+		name = prometheusName(name)
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("keys %q and %q have the same Prometheus metric name %q", other, k, name)
+		}
+		names[name] = k
+		if _, err := fmt.Fprintf(w, "# TYPE %s gauge\n%s %s\n", name, name, prometheusFloat(values[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrometheusHandler returns an http.Handler serving the values dumped from i with WritePrometheus and opts.
+// i is dumped on each request, it should be a pointer to serve up to date values.
+func (e *Encoder) PrometheusHandler(i interface{}, opts *PrometheusOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		if err := e.WritePrometheus(buf, i, opts); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		buf.WriteTo(w)
+	})
+}
+
+// prometheusValue returns the value of numbers and booleans as a float64
+func prometheusValue(i interface{}) (float64, bool) {
+	if n, ok := i.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32:
+		return Float32Value(float32(v.Float())), true
+	case reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func prometheusFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var prometheusLabelReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+// prometheusName replaces the characters of s which are not allowed in metric names by underscores
+func prometheusName(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
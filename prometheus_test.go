@@ -0,0 +1,66 @@
+package dump_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fsamin/go-dump"
+)
+
+type prometheusStats struct {
+	Name    string
+	Ready   bool
+	Latency time.Duration
+	Pool    struct {
+		Max  int
+		Idle uint
+		Load float32
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	var stats prometheusStats
+	stats.Name = "api"
+	stats.Ready = true
+	stats.Latency = time.Millisecond
+	stats.Pool.Max = 20
+	stats.Pool.Idle = 3
+	stats.Pool.Load = 0.1
+
+	e := dump.NewDefaultEncoder()
+	rec := httptest.NewRecorder()
+	e.PrometheusHandler(&stats, &dump.PrometheusOptions{MetricName: "go_dump_config"}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	expected := `# TYPE go_dump_config gauge
+go_dump_config{key="prometheusStats.Latency"} 1e+06
+go_dump_config{key="prometheusStats.Pool.Idle"} 3
+go_dump_config{key="prometheusStats.Pool.Load"} 0.1
+go_dump_config{key="prometheusStats.Pool.Max"} 20
+go_dump_config{key="prometheusStats.Ready"} 1
+`
+	assert.Equal(t, expected, rec.Body.String())
+
+	stats.Pool.Max = 10
+	e.DisableTypePrefix = true
+	rec = httptest.NewRecorder()
+	e.PrometheusHandler(&stats, &dump.PrometheusOptions{MetricName: "app", MetricPerKey: true}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	expected = `# TYPE app_Latency gauge
+app_Latency 1e+06
+# TYPE app_Pool_Idle gauge
+app_Pool_Idle 3
+# TYPE app_Pool_Load gauge
+app_Pool_Load 0.1
+# TYPE app_Pool_Max gauge
+app_Pool_Max 10
+# TYPE app_Ready gauge
+app_Ready 1
+`
+	assert.Equal(t, expected, rec.Body.String())
+}
@@ -0,0 +1,75 @@
+package dump
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// WriteProperties writes the dumped keys and values of i to w as a Java .properties file. Keys and values are
+// escaped following the java.util.Properties format: separators, comment characters, leading spaces and non-ASCII
+// characters are escaped, and multi-line values are written on continuation lines.
+func (e *Encoder) WriteProperties(w io.Writer, i interface{}) error {
+	res, err := e.ToStringMap(i)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(res))
+	for k := range res {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines := strings.Split(strings.Replace(res[k], "\r\n", "\n", -1), "\n")
+		for n := range lines {
+			lines[n] = propertiesEscape(lines[n], false)
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", propertiesEscape(k, true), strings.Join(lines, "\\n\\\n    ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertiesEscape escapes s as a key or a value of a .properties file. All the spaces of keys are escaped,
+// only the leading spaces of values are.
+func propertiesEscape(s string, key bool) string {
+	var b strings.Builder
+	leading := true
+	for _, r := range s {
+		if r != ' ' {
+			leading = false
+		}
+		switch {
+		case r == ' ':
+			if key || leading {
+				b.WriteString(`\ `)
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '\\' || r == '=' || r == ':' || r == '#' || r == '!':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r < 0x20 || r > 0x7e:
+			if r1, r2 := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+				fmt.Fprintf(&b, `\u%04X\u%04X`, r1, r2)
+			} else {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestWriteProperties(t *testing.T) {
+	m := map[string]interface{}{
+		"url":       "jdbc:postgresql://db:5432/app?ssl=true",
+		"comment":   "#not a comment!",
+		"indented":  "  leading spaces",
+		"unicode":   "café 😀",
+		"multiline": "first line\n second line",
+		"key with":  "spaces inside",
+	}
+
+	out := &bytes.Buffer{}
+	err := dump.NewDefaultEncoder().WriteProperties(out, m)
+	assert.NoError(t, err)
+
+	expected := `comment=\#not a comment\!
+indented=\ \ leading spaces
+key_with=spaces inside
+multiline=first line\n\
+    \ second line
+unicode=caf\u00E9 \uD83D\uDE00
+url=jdbc\:postgresql\://db\:5432/app?ssl\=true
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	e := dump.NewDefaultEncoder()
+	e.Formatters = []dump.KeyFormatterFunc{dump.NoFormatter()}
+	err = e.WriteProperties(out, map[string]string{"a key:x": "v"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a\\ key\\:x=v\n", out.String())
+}
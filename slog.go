@@ -0,0 +1,120 @@
+//go:build go1.21
+// +build go1.21
+
+package dump
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sort"
+)
+
+// LogValue returns the dumped keys and values of i as a slog group value
+func LogValue(i interface{}, formatters ...KeyFormatterFunc) slog.Value {
+	if formatters == nil {
+		formatters = []KeyFormatterFunc{WithDefaultFormatter()}
+	}
+	e := NewDefaultEncoder()
+	e.Formatters = formatters
+	return e.LogValue(i)
+}
+
+// LogValue returns the dumped keys and values of i as a slog group value, or the error if i cannot be dumped
+func (e *Encoder) LogValue(i interface{}) slog.Value {
+	attrs, err := e.logAttrs(i)
+	if err != nil {
+		return slog.AnyValue(err)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func (e *Encoder) logAttrs(i interface{}) ([]slog.Attr, error) {
+	res, err := e.ToMap(i)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(res))
+	for k := range res {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, res[k]))
+	}
+	return attrs, nil
+}
+
+// slogHandler is the slog.Handler returned by NewSlogHandler
+type slogHandler struct {
+	handler slog.Handler
+	encoder *Encoder
+}
+
+// NewSlogHandler returns a slog.Handler flattening the struct attributes with the encoder e before passing
+// the records to h. The attributes of a struct, or of a pointer to a struct, are replaced by its dumped keys,
+// prefixed by the key of the attribute, without type prefix. Errors are left as they are.
+// Use the Redaction option of the encoder to keep the fields tagged `dump:"secret"` out of the logs.
+func NewSlogHandler(h slog.Handler, e *Encoder) slog.Handler {
+	return &slogHandler{handler: h, encoder: e}
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	res := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		res.AddAttrs(h.flatten(a)...)
+		return true
+	})
+	return h.handler.Handle(ctx, res)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var res []slog.Attr
+	for _, a := range attrs {
+		res = append(res, h.flatten(a)...)
+	}
+	return &slogHandler{handler: h.handler.WithAttrs(res), encoder: h.encoder}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{handler: h.handler.WithGroup(name), encoder: h.encoder}
+}
+
+// flatten returns the attributes of the dumped keys of a, if a holds a struct, or a itself
+func (h *slogHandler) flatten(a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		var attrs []slog.Attr
+		for _, c := range a.Value.Group() {
+			attrs = append(attrs, h.flatten(c)...)
+		}
+		return []slog.Attr{{Key: a.Key, Value: slog.GroupValue(attrs...)}}
+	case slog.KindAny:
+		i := a.Value.Any()
+		if _, ok := i.(error); ok {
+			return []slog.Attr{a}
+		}
+		v := reflect.ValueOf(i)
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return []slog.Attr{a}
+		}
+		e := *h.encoder
+		e.Prefix = a.Key
+		e.DisableTypePrefix = true
+		attrs, err := e.logAttrs(i)
+		if err != nil {
+			return []slog.Attr{a}
+		}
+		return attrs
+	}
+	return []slog.Attr{a}
+}
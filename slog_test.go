@@ -0,0 +1,69 @@
+//go:build go1.21
+// +build go1.21
+
+package dump_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+type slogConfig struct {
+	Name     string
+	Database struct {
+		Host     string
+		Port     int
+		Password string `dump:"secret"`
+	}
+}
+
+func newTestLogger(buf *bytes.Buffer, e *dump.Encoder) *slog.Logger {
+	h := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	if e == nil {
+		return slog.New(h)
+	}
+	return slog.New(dump.NewSlogHandler(h, e))
+}
+
+func TestLogValue(t *testing.T) {
+	var cfg slogConfig
+	cfg.Name = "api"
+	cfg.Database.Host = "db"
+	cfg.Database.Port = 5432
+
+	buf := new(bytes.Buffer)
+	newTestLogger(buf, nil).Info("starting", "config", dump.LogValue(cfg))
+	assert.Equal(t, "level=INFO msg=starting config.slogConfig.Database.Host=db config.slogConfig.Database.Password=\"\" config.slogConfig.Database.Port=5432 config.slogConfig.Name=api\n", buf.String())
+}
+
+func TestSlogHandler(t *testing.T) {
+	var cfg slogConfig
+	cfg.Name = "api"
+	cfg.Database.Host = "db"
+	cfg.Database.Port = 5432
+	cfg.Database.Password = "s3cr3t"
+
+	e := dump.NewDefaultEncoder()
+	e.Redaction = "********"
+
+	buf := new(bytes.Buffer)
+	logger := newTestLogger(buf, e).With("service", "api")
+	logger.Info("starting", "Config", &cfg, "attempt", 1)
+	assert.Equal(t, "level=INFO msg=starting service=api Config.Database.Host=db Config.Database.Password=******** Config.Database.Port=5432 Config.Name=api attempt=1\n", buf.String())
+
+	buf.Reset()
+	logger.WithGroup("req").Error("failed", "db", cfg.Database, "err", assert.AnError)
+	assert.Equal(t, "level=ERROR msg=failed service=api req.db.Host=db req.db.Password=******** req.db.Port=5432 req.err=\"assert.AnError general error for testing\"\n", buf.String())
+}
@@ -0,0 +1,166 @@
+package dump
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableOptions holds the options of WriteMarkdown and WriteHTML
+type TableOptions struct {
+	// Group splits the tables by top level key
+	Group bool
+}
+
+// tableGroup is a group of rows of the tables written by WriteMarkdown and WriteHTML
+type tableGroup struct {
+	name string
+	keys []string
+}
+
+// tableGroups dumps i and returns its sorted keys, in a single unnamed group or grouped by top level key
+// if the Group option is set
+func (e *Encoder) tableGroups(i interface{}, opts *TableOptions) (map[string]string, []tableGroup, error) {
+	res, err := e.ToStringMap(i)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, 0, len(res))
+	for k := range res {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if opts == nil || !opts.Group {
+		return res, []tableGroup{{keys: keys}}, nil
+	}
+
+	typePrefix := !e.DisableTypePrefix && valueFromInterface(i).Kind() == reflect.Struct
+	var groups []tableGroup
+	indexes := map[string]int{}
+	for _, k := range keys {
+		name := e.topLevelKey(k, typePrefix)
+		n, ok := indexes[name]
+		if !ok {
+			n = len(groups)
+			indexes[name] = n
+			groups = append(groups, tableGroup{name: name})
+		}
+		groups[n].keys = append(groups[n].keys, k)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].name < groups[j].name })
+	return res, groups, nil
+}
+
+// topLevelKey returns the first segment of the key k below the prefix and, if typePrefix is set, the type prefix
+func (e *Encoder) topLevelKey(k string, typePrefix bool) string {
+	if e.Prefix != "" {
+		k = strings.TrimPrefix(k, e.Prefix+e.Separator)
+	}
+	if typePrefix {
+		if i := strings.Index(k, e.Separator); i >= 0 {
+			k = k[i+len(e.Separator):]
+		}
+	}
+	if i := strings.Index(k, e.Separator); i > 0 {
+		k = k[:i]
+	}
+	if i := strings.Index(k, "["); i > 0 && e.ArrayJSONNotation {
+		k = k[:i]
+	}
+	return k
+}
+
+// WriteMarkdown writes the dumped keys and values of i to w as a Markdown table. Tables are split by top level
+// key, under a heading, if the Group option is set. opts may be nil.
+func (e *Encoder) WriteMarkdown(w io.Writer, i interface{}, opts *TableOptions) error {
+	res, groups, err := e.tableGroups(i, opts)
+	if err != nil {
+		return err
+	}
+	for n, g := range groups {
+		if n > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if g.name != "" {
+			if _, err := fmt.Fprintf(w, "### %s\n\n", markdownEscape(g.name)); err != nil {
+				return err
+			}
+		}
+
+		rows := [][2]string{{"KEY", "Value"}}
+		for _, k := range g.keys {
+			rows = append(rows, [2]string{markdownEscape(k), markdownEscape(res[k])})
+		}
+		var widths [2]int
+		for _, row := range rows {
+			for c := range row {
+				if l := utf8.RuneCountInString(row[c]); l > widths[c] {
+					widths[c] = l
+				}
+			}
+		}
+		for n, row := range rows {
+			if _, err := fmt.Fprintf(w, "| %s | %s |\n", pad(row[0], widths[0]), pad(row[1], widths[1])); err != nil {
+				return err
+			}
+			if n == 0 {
+				if _, err := fmt.Fprintf(w, "| %s | %s |\n", strings.Repeat("-", widths[0]), strings.Repeat("-", widths[1])); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteHTML writes the dumped keys and values of i to w as an HTML table. The table has a body per top level
+// key, with the key as header, if the Group option is set. opts may be nil.
+func (e *Encoder) WriteHTML(w io.Writer, i interface{}, opts *TableOptions) error {
+	res, groups, err := e.tableGroups(i, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "<table>\n  <thead>\n    <tr><th>KEY</th><th>Value</th></tr>\n  </thead>\n"); err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if _, err := io.WriteString(w, "  <tbody>\n"); err != nil {
+			return err
+		}
+		if g.name != "" {
+			if _, err := fmt.Fprintf(w, "    <tr><th colspan=\"2\">%s</th></tr>\n", htmlEscape(g.name)); err != nil {
+				return err
+			}
+		}
+		for _, k := range g.keys {
+			if _, err := fmt.Fprintf(w, "    <tr><td>%s</td><td>%s</td></tr>\n", htmlEscape(k), htmlEscape(res[k])); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, "  </tbody>\n"); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "</table>\n")
+	return err
+}
+
+var markdownReplacer = strings.NewReplacer("\\", "\\\\", "|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func markdownEscape(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+func htmlEscape(s string) string {
+	return strings.Replace(html.EscapeString(s), "\n", "<br>", -1)
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
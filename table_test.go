@@ -0,0 +1,91 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	type T struct {
+		A int
+		B string
+		C Tbis
+	}
+
+	a := T{23, "foo | bar", Tbis{"lol", "multi\nline"}}
+
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	err := e.WriteMarkdown(out, a, nil)
+	assert.NoError(t, err)
+
+	expected := `| KEY      | Value         |
+| -------- | ------------- |
+| T.A      | 23            |
+| T.B      | foo \| bar    |
+| T.C.Cbis | lol           |
+| T.C.Cter | multi<br>line |
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = e.WriteMarkdown(out, a, &dump.TableOptions{Group: true})
+	assert.NoError(t, err)
+
+	expected = `### A
+
+| KEY | Value |
+| --- | ----- |
+| T.A | 23    |
+
+### B
+
+| KEY | Value      |
+| --- | ---------- |
+| T.B | foo \| bar |
+
+### C
+
+| KEY      | Value         |
+| -------- | ------------- |
+| T.C.Cbis | lol           |
+| T.C.Cter | multi<br>line |
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteHTML(t *testing.T) {
+	type T struct {
+		A string
+		C Tbis
+	}
+
+	a := T{"<b>bold</b>", Tbis{"lol", "lel"}}
+
+	out := &bytes.Buffer{}
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	err := e.WriteHTML(out, a, &dump.TableOptions{Group: true})
+	assert.NoError(t, err)
+
+	expected := `<table>
+  <thead>
+    <tr><th>KEY</th><th>Value</th></tr>
+  </thead>
+  <tbody>
+    <tr><th colspan="2">A</th></tr>
+    <tr><td>A</td><td>&lt;b&gt;bold&lt;/b&gt;</td></tr>
+  </tbody>
+  <tbody>
+    <tr><th colspan="2">C</th></tr>
+    <tr><td>C.Cbis</td><td>lol</td></tr>
+    <tr><td>C.Cter</td><td>lel</td></tr>
+  </tbody>
+</table>
+`
+	assert.Equal(t, expected, out.String())
+}
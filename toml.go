@@ -0,0 +1,174 @@
+package dump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WriteTOML writes i to w as a TOML document, nested the same way as the dumped keys. Keys are named the way
+// go-dump names them, with the formatters and struct tags options of the encoder. Structs and maps are written
+// as tables, arrays of structs and maps as arrays of tables, other arrays inline, and values keep their types.
+// Nil values are omitted since TOML has no null value.
+func (e *Encoder) WriteTOML(w io.Writer, i interface{}) error {
+	root, err := e.document(i, "TOML")
+	if err != nil {
+		return err
+	}
+	if root.isList() {
+		return fmt.Errorf("unable to write %T as TOML: the document is not a table", i)
+	}
+	var lines []string
+	tomlTable(&lines, nil, root.children)
+	if len(lines) == 0 {
+		return nil
+	}
+	_, err = io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// tomlTable writes the key/value pairs of the table at path, then its sub-tables and arrays of tables
+func tomlTable(lines *[]string, path []string, nodes []*node) {
+	var tables, arrays []*node
+	for _, n := range nodes {
+		switch {
+		case n.isList() && len(n.children) > 0 && isTableArray(n.children):
+			arrays = append(arrays, n)
+		case n.isContainer() && !n.isList() && len(n.children) > 0:
+			tables = append(tables, n)
+		default:
+			if v, ok := tomlInline(n); ok {
+				*lines = append(*lines, tomlKey(n.label)+" = "+v)
+			}
+		}
+	}
+	for _, n := range tables {
+		p := append(path[:len(path):len(path)], tomlKey(n.label))
+		if len(*lines) > 0 {
+			*lines = append(*lines, "")
+		}
+		*lines = append(*lines, "["+strings.Join(p, ".")+"]")
+		tomlTable(lines, p, n.children)
+	}
+	for _, n := range arrays {
+		p := append(path[:len(path):len(path)], tomlKey(n.label))
+		for _, item := range n.children {
+			if len(*lines) > 0 {
+				*lines = append(*lines, "")
+			}
+			*lines = append(*lines, "[["+strings.Join(p, ".")+"]]")
+			tomlTable(lines, p, item.children)
+		}
+	}
+}
+
+// isTableArray returns true if all the items are structs or maps
+func isTableArray(items []*node) bool {
+	for _, item := range items {
+		if !item.isContainer() || item.isList() {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlInline returns the node as an inline TOML value, ok is false for nil values
+func tomlInline(n *node) (string, bool) {
+	if !n.isContainer() {
+		return tomlScalar(n.scalar())
+	}
+	var values []string
+	for _, c := range n.children {
+		v, ok := tomlInline(c)
+		if !ok {
+			continue
+		}
+		if n.isList() {
+			values = append(values, v)
+		} else {
+			values = append(values, tomlKey(c.label)+" = "+v)
+		}
+	}
+	if n.isList() {
+		return "[" + strings.Join(values, ", ") + "]", true
+	}
+	if len(values) == 0 {
+		return "{}", true
+	}
+	return "{ " + strings.Join(values, ", ") + " }", true
+}
+
+func tomlScalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case bool:
+		return strconv.FormatBool(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return "inf", true
+		case math.IsInf(v, -1):
+			return "-inf", true
+		case math.IsNaN(v):
+			return "nan", true
+		}
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s, true
+	case json.Number:
+		return v.String(), true
+	case string:
+		return tomlString(v), true
+	}
+	return "", false
+}
+
+var tomlBareKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(k string) string {
+	if tomlBareKeyRegexp.MatchString(k) {
+		return k
+	}
+	return tomlString(k)
+}
+
+// tomlString returns s as a TOML basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,258 @@
+package dump
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// node is an entry of the hierarchy rebuilt from the flattened keys
+type node struct {
+	key      string
+	label    string
+	value    interface{}
+	hasValue bool
+	meta     Meta
+	hasMeta  bool
+	children []*node
+}
+
+// isList returns true if the children of the node are the items of an array or a slice
+func (n *node) isList() bool {
+	return n.hasMeta && (n.meta.Kind == reflect.Array || n.meta.Kind == reflect.Slice)
+}
+
+// isContainer returns true if the node is a struct, a map or an array rather than a value
+func (n *node) isContainer() bool {
+	if len(n.children) > 0 {
+		return true
+	}
+	if n.hasValue || !n.hasMeta || n.meta.Nil {
+		return false
+	}
+	switch n.meta.Kind {
+	case reflect.Struct, reflect.Map, reflect.Array, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+// scalar returns the value of the node as a bool, an int64, an uint64, a float64, a json.Number or a string,
+// or nil if the value is nil. Values implementing fmt.Stringer are returned as strings.
+func (n *node) scalar() interface{} {
+	if !n.hasValue || n.hasMeta && n.meta.Nil && n.value == "" {
+		return nil
+	}
+	switch v := n.value.(type) {
+	case json.Number:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	}
+	v := reflect.ValueOf(n.value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32:
+		return Float32Value(float32(v.Float()))
+	case reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	}
+	return printValue(n.value)
+}
+
+// tree dumps i and rebuilds the hierarchy of its keys. A key is nested under the longest other key
+// it starts with, followed by the separator or, with ArrayJSONNotation, by an index.
+// The returned root has the top level keys as children.
+func (e *Encoder) tree(i interface{}) (*node, error) {
+	data, meta, err := e.ToMapWithMeta(i)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*node{}
+	for k, v := range data {
+		nodes[k] = &node{key: k, label: k, value: v, hasValue: true}
+	}
+	for k, m := range meta {
+		n, ok := nodes[k]
+		if !ok {
+			n = &node{key: k, label: k}
+			nodes[k] = n
+		}
+		n.meta = m
+		n.hasMeta = true
+	}
+
+	root, ok := nodes[""]
+	if !ok {
+		root = &node{}
+	}
+	for k, n := range nodes {
+		if k == "" {
+			continue
+		}
+		parent, label, ok := e.parentKey(k, nodes)
+		if !ok {
+			root.children = append(root.children, n)
+			continue
+		}
+		n.label = label
+		nodes[parent].children = append(nodes[parent].children, n)
+	}
+	for _, n := range nodes {
+		sortNodes(n.children)
+	}
+	sortNodes(root.children)
+	return root, nil
+}
+
+// document dumps i into a tree to be written as a nested document in format. It returns an error if the keys
+// cannot be nested without dropping values: a value at the top level, or a value with other keys nested under it.
+func (e *Encoder) document(i interface{}, format string) (*node, error) {
+	root, err := e.tree(i)
+	if err != nil {
+		return nil, err
+	}
+	if root.hasValue {
+		return nil, fmt.Errorf("unable to write %T as %s: the top level is a value", i, format)
+	}
+	if err := checkNesting(root.children, format); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// checkNesting returns an error if a node holding a value, rather than a struct, a map or an array,
+// has children
+func checkNesting(nodes []*node, format string) error {
+	for _, n := range nodes {
+		if n.hasValue && n.hasMeta && len(n.children) > 0 {
+			switch n.meta.Kind {
+			case reflect.Struct, reflect.Map, reflect.Array, reflect.Slice:
+			default:
+				return fmt.Errorf("unable to write %s as %s: the key has a value and nested keys such as %s", n.key, format, n.children[0].key)
+			}
+		}
+		if err := checkNesting(n.children, format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parentKey returns the longest key of nodes which is a parent of key, and the label of key below it
+func (e *Encoder) parentKey(key string, nodes map[string]*node) (string, string, bool) {
+	for p := len(key) - 1; p > 0; p-- {
+		var label string
+		switch {
+		case e.Separator != "" && strings.HasPrefix(key[p:], e.Separator):
+			label = key[p+len(e.Separator):]
+		case e.ArrayJSONNotation && key[p] == '[':
+			label = key[p:]
+		default:
+			continue
+		}
+		if _, ok := nodes[key[:p]]; ok && label != "" {
+			return key[:p], label, true
+		}
+	}
+	return "", "", false
+}
+
+// sortNodes sorts nodes by label, comparing numbers in labels by value so that items are kept in order
+func sortNodes(nodes []*node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return naturalLess(nodes[i].label, nodes[j].label)
+	})
+}
+
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
+
+var packagePathRegexp = regexp.MustCompile(`(?:[\w.\-~]+/)+`)
+
+// shortType removes the package paths from the fully qualified type t
+func shortType(t string) string {
+	return packagePathRegexp.ReplaceAllString(t, "")
+}
+
+// Tdump formats and displays the passed arguments to the writer of the encoder as an indented tree,
+// with the types and the lengths of the values inline.
+func (e *Encoder) Tdump(i interface{}) error {
+	root, err := e.tree(i)
+	if err != nil {
+		return err
+	}
+	for _, n := range root.children {
+		if err := e.tdumpNode(n, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) tdumpNode(n *node, depth int) error {
+	line := strings.Repeat("  ", depth) + n.label + ":"
+	if n.hasMeta && n.meta.Type != "" {
+		line += " (" + shortType(n.meta.Type) + ")"
+	}
+	if n.hasMeta && (n.meta.Kind == reflect.Map || n.isList()) && !n.meta.Nil {
+		line += fmt.Sprintf(" len=%d", n.meta.Len)
+	}
+	var value string
+	if n.hasValue {
+		value = printValue(n.value)
+	}
+	switch {
+	case value != "":
+		line += " " + value
+	case n.hasMeta && n.meta.Nil:
+		line += " <nil>"
+	}
+	if _, err := fmt.Fprintln(e.writer, line); err != nil {
+		return err
+	}
+	for _, c := range n.children {
+		if err := e.tdumpNode(c, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package dump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fsamin/go-dump"
+)
+
+func TestTdump(t *testing.T) {
+	type Pool struct {
+		Max int
+	}
+	type Config struct {
+		Name    string
+		Hosts   []string
+		Pool    *Pool
+		Backup  *Pool
+		Labels  map[string]string
+		Servers []Tbis
+	}
+
+	a := Config{
+		Name:   "api",
+		Hosts:  []string{"h0", "h1", "h2", "h3", "h4", "h5", "h6", "h7", "h8", "h9", "h10"},
+		Pool:   &Pool{Max: 20},
+		Labels: map[string]string{"env": "prod"},
+		Servers: []Tbis{
+			{Cbis: "a", Cter: "b"},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	err := dump.Tdump(out, a)
+	assert.NoError(t, err)
+
+	expected := `Config: (go-dump_test.Config)
+  Backup: (*go-dump_test.Pool) <nil>
+  Hosts: ([]string) len=11
+    Hosts0: (string) h0
+    Hosts1: (string) h1
+    Hosts2: (string) h2
+    Hosts3: (string) h3
+    Hosts4: (string) h4
+    Hosts5: (string) h5
+    Hosts6: (string) h6
+    Hosts7: (string) h7
+    Hosts8: (string) h8
+    Hosts9: (string) h9
+    Hosts10: (string) h10
+  Labels: (map[string]string) len=1
+    env: (string) prod
+  Name: (string) api
+  Pool: (*go-dump_test.Pool)
+    Max: (int) 20
+  Servers: ([]go-dump_test.Tbis) len=1
+    Servers0: (go-dump_test.Tbis)
+      Cbis: (string) a
+      Cter: (string) b
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	e := dump.NewEncoder(out)
+	e.DisableTypePrefix = true
+	e.ArrayJSONNotation = true
+	err = e.Tdump([]Tbis{{Cbis: "a"}})
+	assert.NoError(t, err)
+
+	expected = `[0]: (go-dump_test.Tbis)
+  Cbis: (string) a
+  Cter: (string)
+`
+	assert.Equal(t, expected, out.String())
+}
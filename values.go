@@ -0,0 +1,290 @@
+package dump
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToValues dumps i as url.Values, to build query strings and form bodies. The items of arrays and slices of values
+// are repeated values of the key of the array, or are indexed keys such as a[0] if ArrayJSONNotation is set.
+func (e *Encoder) ToValues(i interface{}) (url.Values, error) {
+	root, err := e.tree(i)
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	e.addValues(values, root)
+	return values, nil
+}
+
+func (e *Encoder) addValues(values url.Values, n *node) {
+	for _, c := range n.children {
+		if c.hasValue {
+			key := c.key
+			if n.isList() && n.key != "" && !e.ArrayJSONNotation && len(c.children) == 0 {
+				key = n.key
+			}
+			values.Add(key, printValue(c.value))
+		}
+		e.addValues(values, c)
+	}
+}
+
+// FromValues decodes values, as written by ToValues with the same options, into i which must be a non nil pointer.
+// Keys without value in values leave the target fields unchanged.
+func (e *Encoder) FromValues(values url.Values, i interface{}) error {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("cannot decode values into %T: not a non nil pointer", i)
+	}
+	var roots []string
+	if v.Elem().Kind() == reflect.Struct && !e.DisableTypePrefix {
+		roots = append(roots, v.Elem().Type().Name())
+	}
+	return e.decodeValues(values, v.Elem(), roots)
+}
+
+func (e *Encoder) decodeValues(values url.Values, v reflect.Value, roots []string) error {
+	if v.CanAddr() {
+		if _, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return e.decodeValue(values, v, roots)
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		// Nil pointers are dumped as empty values
+		if vals := values[e.key(roots)]; !e.hasValues(values, roots) || len(vals) == 1 && vals[0] == "" {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return e.decodeValues(values, v.Elem(), roots)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanSet() {
+				continue
+			}
+			name := v.Type().Field(i).Name
+			if e.ExtraFields.UseJSONTag {
+				tagValues := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")
+				if len(tagValues) > 0 && tagValues[0] != "omitempty" && tagValues[0] != "" {
+					name = tagValues[0]
+				}
+			}
+			if err := e.decodeValues(values, v.Field(i), append(roots[:len(roots):len(roots)], name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Array, reflect.Slice:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return e.decodeValue(values, v, roots)
+		}
+		return e.decodeItems(values, v, roots)
+	case reflect.Map:
+		return e.decodeMap(values, v, roots)
+	}
+	return e.decodeValue(values, v, roots)
+}
+
+// itemRoots returns the roots of the item i of the array of roots, as fDumpArray computes them
+func (e *Encoder) itemRoots(roots []string, i int) []string {
+	if len(roots) == 0 {
+		if e.ArrayJSONNotation {
+			return []string{fmt.Sprintf("[%d]", i)}
+		}
+		return []string{fmt.Sprintf("%s%d", e.Prefix, i)}
+	}
+	l := roots[len(roots)-1]
+	if e.ArrayJSONNotation {
+		return append(roots[:len(roots)-1:len(roots)-1], fmt.Sprintf("%s[%d]", l, i))
+	}
+	return append(roots[:len(roots):len(roots)], fmt.Sprintf("%s%d", l, i))
+}
+
+func (e *Encoder) decodeItems(values url.Values, v reflect.Value, roots []string) error {
+	var n int
+	repeated, isRepeated := values[e.key(roots)]
+	if isRepeated && !e.ArrayJSONNotation {
+		n = len(repeated)
+	} else {
+		for e.hasValues(values, e.itemRoots(roots, n)) {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	}
+	for i := 0; i < n && i < v.Len(); i++ {
+		var err error
+		if isRepeated && !e.ArrayJSONNotation {
+			err = e.setValue(v.Index(i), repeated[i], e.key(roots))
+		} else {
+			err = e.decodeValues(values, v.Index(i), e.itemRoots(roots, i))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeMap decodes the entries nested under roots into the map v. Entries holding structs, maps or arrays are
+// named by the first segment of their keys and decoded recursively, the keys of other entries are the whole rest.
+func (e *Encoder) decodeMap(values url.Values, v reflect.Value, roots []string) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("cannot decode values into %s: map keys are not strings", v.Type())
+	}
+	prefix := e.key(roots)
+	if prefix != "" {
+		prefix += e.Separator
+	}
+	t := v.Type().Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var nested, typePrefix bool
+	switch t.Kind() {
+	case reflect.Struct:
+		// fDumpMap nests the fields of structs under their type name
+		nested = !reflect.PtrTo(t).Implements(textUnmarshalerType)
+		typePrefix = nested && !e.DisableTypePrefix
+	case reflect.Map, reflect.Array:
+		nested = true
+	case reflect.Slice:
+		nested = t.Elem().Kind() != reflect.Uint8
+	}
+
+	names := map[string]bool{}
+	for k, vals := range values {
+		if !strings.HasPrefix(k, prefix) || len(vals) == 0 {
+			continue
+		}
+		name := k[len(prefix):]
+		if nested {
+			if i := strings.Index(name, e.Separator); e.Separator != "" && i >= 0 {
+				name = name[:i]
+			}
+			if i := strings.Index(name, "["); e.ArrayJSONNotation && i > 0 {
+				name = name[:i]
+			}
+		}
+		if name != "" {
+			names[name] = true
+		}
+	}
+	for name := range names {
+		croots := append(roots[:len(roots):len(roots)], name)
+		if typePrefix {
+			croots = append(croots, t.Name())
+		}
+		item := reflect.New(v.Type().Elem()).Elem()
+		if err := e.decodeValues(values, item, croots); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), item)
+	}
+	return nil
+}
+
+// hasValues returns true if values holds the key of roots or a key nested under it
+func (e *Encoder) hasValues(values url.Values, roots []string) bool {
+	k := e.key(roots)
+	for key := range values {
+		if key == k || k == "" || strings.HasPrefix(key, k+e.Separator) || strings.HasPrefix(key, k+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Encoder) decodeValue(values url.Values, v reflect.Value, roots []string) error {
+	k := e.key(roots)
+	vals, ok := values[k]
+	if !ok || len(vals) == 0 {
+		return nil
+	}
+	return e.setValue(v, vals[0], k)
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setValue parses s into v, the value of the key k
+func (e *Encoder) setValue(v reflect.Value, s string, k string) error {
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(s)); err != nil {
+				return fmt.Errorf("invalid value %q for key %s: %v", s, k, err)
+			}
+			return nil
+		}
+	}
+	var err error
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return e.setValue(v.Elem(), s, k)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(s))
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if v.Type() == durationType {
+			var d time.Duration
+			d, err = time.ParseDuration(s)
+			i = int64(d)
+		} else {
+			i, err = strconv.ParseInt(s, 10, v.Type().Bits())
+		}
+		if err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, v.Type().Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(s))
+			return nil
+		}
+		err = fmt.Errorf("unsupported type %s", v.Type())
+	default:
+		err = fmt.Errorf("unsupported type %s", v.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for key %s: %v", s, k, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package dump_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fsamin/go-dump"
+)
+
+type valuesFilter struct {
+	Field string
+	Value string
+}
+
+type valuesRequest struct {
+	Query   string
+	Page    int
+	Exact   bool
+	Timeout time.Duration
+	Tags    []string
+	Filters []valuesFilter
+	Sort    *valuesFilter
+	Labels  map[string]string
+}
+
+func TestToValues(t *testing.T) {
+	req := valuesRequest{
+		Query:   "go dump",
+		Page:    2,
+		Timeout: time.Second,
+		Tags:    []string{"a", "b", "c"},
+		Filters: []valuesFilter{{Field: "lang", Value: "go"}},
+		Labels:  map[string]string{"env": "prod"},
+	}
+
+	e := dump.NewDefaultEncoder()
+	e.DisableTypePrefix = true
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	values, err := e.ToValues(req)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"query":                  {"go dump"},
+		"page":                   {"2"},
+		"exact":                  {"false"},
+		"timeout":                {"1s"},
+		"tags":                   {"a", "b", "c"},
+		"filters.filters0.field": {"lang"},
+		"filters.filters0.value": {"go"},
+		"sort":                   {""},
+		"labels.env":             {"prod"},
+	}, values)
+
+	var decoded valuesRequest
+	require.NoError(t, e.FromValues(values, &decoded))
+	assert.Equal(t, req, decoded)
+
+	e.ArrayJSONNotation = true
+	values, err = e.ToValues(req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, values["tags[0]"])
+	assert.Equal(t, []string{"lang"}, values["filters[0].field"])
+	assert.Equal(t, "exact=false&filters%5B0%5D.field=lang&filters%5B0%5D.value=go&labels.env=prod&page=2&query=go+dump&sort=&tags%5B0%5D=a&tags%5B1%5D=b&tags%5B2%5D=c&timeout=1s", values.Encode())
+
+	decoded = valuesRequest{}
+	require.NoError(t, e.FromValues(values, &decoded))
+	assert.Equal(t, req, decoded)
+
+	e = dump.NewDefaultEncoder()
+	require.NoError(t, e.FromValues(url.Values{"valuesRequest.Sort.Field": {"date"}, "valuesRequest.Page": {"3"}}, &decoded))
+	assert.Equal(t, 3, decoded.Page)
+	assert.Equal(t, &valuesFilter{Field: "date"}, decoded.Sort)
+
+	assert.Error(t, e.FromValues(url.Values{"valuesRequest.Page": {"three"}}, &decoded))
+	assert.Error(t, e.FromValues(url.Values{}, decoded))
+}
+
+func TestFromValuesMaps(t *testing.T) {
+	type T struct {
+		Filters  map[string]valuesFilter
+		Pointers map[string]*valuesFilter
+		Lists    map[string][]string
+		Nested   map[string]map[string]int
+		Labels   map[string]string
+	}
+	a := T{
+		Filters:  map[string]valuesFilter{"lang": {Field: "lang", Value: "go"}, "os": {Field: "os", Value: "linux"}},
+		Pointers: map[string]*valuesFilter{"sort": {Field: "date"}},
+		Lists:    map[string][]string{"tags": {"a", "b"}},
+		Nested:   map[string]map[string]int{"limits": {"max": 10}},
+		Labels:   map[string]string{"team.name": "core"},
+	}
+
+	for _, disableTypePrefix := range []bool{false, true} {
+		e := dump.NewDefaultEncoder()
+		e.DisableTypePrefix = disableTypePrefix
+		values, err := e.ToValues(a)
+		require.NoError(t, err)
+
+		var decoded T
+		require.NoError(t, e.FromValues(values, &decoded))
+		assert.Equal(t, a, decoded)
+	}
+}
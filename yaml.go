@@ -0,0 +1,133 @@
+package dump
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WriteYAML writes i to w as a YAML document, nested the same way as the dumped keys. Keys are named the way
+// go-dump names them, with the formatters and struct tags options of the encoder, arrays are written as
+// sequences and values keep their types.
+func (e *Encoder) WriteYAML(w io.Writer, i interface{}) error {
+	root, err := e.document(i, "YAML")
+	if err != nil {
+		return err
+	}
+	var lines []string
+	switch {
+	case root.isList():
+		lines = yamlSequence(root.children, 0)
+	case len(root.children) > 0:
+		lines = yamlMapping(root.children, 0)
+	default:
+		lines = []string{"{}"}
+	}
+	_, err = io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func yamlMapping(nodes []*node, indent int) []string {
+	var lines []string
+	prefix := strings.Repeat(" ", indent)
+	for _, n := range nodes {
+		key := prefix + yamlString(n.label) + ":"
+		switch {
+		case !n.isContainer():
+			lines = append(lines, key+" "+yamlScalar(n.scalar()))
+		case len(n.children) == 0 && n.isList():
+			lines = append(lines, key+" []")
+		case len(n.children) == 0:
+			lines = append(lines, key+" {}")
+		case n.isList():
+			lines = append(lines, key)
+			lines = append(lines, yamlSequence(n.children, indent+2)...)
+		default:
+			lines = append(lines, key)
+			lines = append(lines, yamlMapping(n.children, indent+2)...)
+		}
+	}
+	return lines
+}
+
+func yamlSequence(nodes []*node, indent int) []string {
+	var lines []string
+	prefix := strings.Repeat(" ", indent)
+	for _, n := range nodes {
+		var item []string
+		switch {
+		case !n.isContainer():
+			lines = append(lines, prefix+"- "+yamlScalar(n.scalar()))
+			continue
+		case len(n.children) == 0 && n.isList():
+			lines = append(lines, prefix+"- []")
+			continue
+		case len(n.children) == 0:
+			lines = append(lines, prefix+"- {}")
+			continue
+		case n.isList():
+			item = yamlSequence(n.children, indent+2)
+		default:
+			item = yamlMapping(n.children, indent+2)
+		}
+		// The first line of the item is written after the dash
+		item[0] = prefix + "- " + item[0][indent+2:]
+		lines = append(lines, item...)
+	}
+	return lines
+}
+
+func yamlScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return ".inf"
+		case math.IsInf(v, -1):
+			return "-.inf"
+		case math.IsNaN(v):
+			return ".nan"
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	}
+	return "null"
+}
+
+var (
+	yamlPlainRegexp    = regexp.MustCompile(`^[^\s\-?:,\[\]{}#&*!|>'"%@` + "`" + `][^\n\r\t]*$`)
+	yamlReservedRegexp = regexp.MustCompile(`^(?i:~|null|true|false|yes|no|on|off|y|n|[-+]?(\.inf|\.nan|[0-9][0-9_.:eE+\-xXoObB]*))$`)
+)
+
+// yamlString returns s as a plain YAML scalar, or double quoted if it could be read as another type or
+// holds special characters
+func yamlString(s string) string {
+	if yamlPlainRegexp.MatchString(s) && !yamlReservedRegexp.MatchString(s) && s == strings.TrimSpace(s) &&
+		!strings.Contains(s, ": ") && !strings.Contains(s, " #") && !strings.HasSuffix(s, ":") && isPrintable(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if !strconv.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}